@@ -0,0 +1,246 @@
+// Package algo contains execution algorithms that work parent orders through the FalconX
+// REST API as a series of child orders.
+package algo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+const defaultQuantityDecimals = 8
+
+// State is the lifecycle state of an algorithm.
+type State string
+
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StatePaused    State = "paused"
+	StateCompleted State = "completed"
+	StateStopped   State = "stopped"
+	StateCancelled State = "cancelled"
+	StateFailed    State = "failed"
+)
+
+var (
+	// ErrCancelled is returned by Run when the algorithm was cancelled.
+	ErrCancelled = errors.New("algo cancelled")
+	// ErrPriceLimit is returned by Run when the price limit stopped the algorithm.
+	ErrPriceLimit = errors.New("algo stopped at price limit")
+	// ErrAlreadyStarted is returned when Run is called more than once.
+	ErrAlreadyStarted = errors.New("algo already started")
+)
+
+// Progress is reported after every child order and when the algorithm finishes.
+type Progress struct {
+	State State
+	// Target is the parent quantity in base token.
+	Target float64
+	// Filled is the filled quantity in base token.
+	Filled float64
+	// AveragePrice is the quantity-weighted fill price in quote token.
+	AveragePrice float64
+	// FeesUSD is the sum of fee_usd over all child fills.
+	FeesUSD float64
+	// Children is the number of child orders sent.
+	Children int
+	// LastOrder is the most recent child order response.
+	LastOrder *clients.OrderResponse
+	Err       error
+}
+
+// Remaining is the quantity still to be filled.
+func (progress Progress) Remaining() float64 {
+	return math.Max(progress.Target-progress.Filled, 0)
+}
+
+func (progress *Progress) addFill(quantity, price, feesUSD float64) {
+	notional := progress.AveragePrice*progress.Filled + price*quantity
+	progress.Filled += quantity
+	progress.FeesUSD += feesUSD
+	if progress.Filled > 0 {
+		progress.AveragePrice = notional / progress.Filled
+	}
+}
+
+// control implements pause, resume and cancel for an algorithm's run loop.
+type control struct {
+	mu      sync.Mutex
+	started bool
+	paused  bool
+	// changed is closed and replaced whenever paused flips.
+	changed   chan struct{}
+	cancelled chan struct{}
+	cause     error
+	cancel    sync.Once
+}
+
+func newControl() *control {
+	return &control{changed: make(chan struct{}), cancelled: make(chan struct{})}
+}
+
+func (c *control) start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return ErrAlreadyStarted
+	}
+	c.started = true
+	return nil
+}
+
+// Pause stops new child orders from being sent until Resume is called. An order already
+// in flight completes normally.
+func (c *control) Pause() {
+	c.setPaused(true)
+}
+
+// Resume continues a paused algorithm.
+func (c *control) Resume() {
+	c.setPaused(false)
+}
+
+func (c *control) setPaused(paused bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused != paused {
+		c.paused = paused
+		close(c.changed)
+		c.changed = make(chan struct{})
+	}
+}
+
+// Cancel stops the algorithm. No further child orders are sent.
+func (c *control) Cancel() {
+	c.cancelWith(ErrCancelled)
+}
+
+func (c *control) cancelWith(cause error) {
+	c.cancel.Do(func() {
+		c.mu.Lock()
+		c.cause = cause
+		c.mu.Unlock()
+		close(c.cancelled)
+	})
+}
+
+// cancelCause returns the error Run reports after a cancellation.
+func (c *control) cancelCause() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cause
+}
+
+// watchKillSwitch cancels the algorithm when killSwitch trips, until stop is called.
+func (c *control) watchKillSwitch(killSwitch *clients.KillSwitch) (stop func()) {
+	if err := killSwitch.Check(); err != nil {
+		c.cancelWith(err)
+		return func() {}
+	}
+
+	quit := make(chan struct{})
+	go func() {
+		select {
+		case <-killSwitch.Done():
+			cause := killSwitch.Check()
+			if cause == nil {
+				cause = clients.ErrKillSwitchTripped
+			}
+			c.cancelWith(cause)
+		case <-quit:
+		}
+	}()
+	return func() { close(quit) }
+}
+
+// Paused reports whether the algorithm is paused.
+func (c *control) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+// waitUntil blocks until t while honouring pause, cancel and ctx. Time spent paused pushes
+// t back; the total is returned so the caller can shift the rest of its schedule.
+func (c *control) waitUntil(ctx context.Context, t time.Time) (time.Duration, error) {
+	var pausedFor time.Duration
+	for {
+		c.mu.Lock()
+		paused, changed := c.paused, c.changed
+		c.mu.Unlock()
+
+		if paused {
+			pausedAt := time.Now()
+			select {
+			case <-changed:
+				pausedFor += time.Since(pausedAt)
+				t = t.Add(time.Since(pausedAt))
+				continue
+			case <-c.cancelled:
+				return pausedFor, c.cancelCause()
+			case <-ctx.Done():
+				return pausedFor, ctx.Err()
+			}
+		}
+
+		timer := time.NewTimer(time.Until(t))
+		select {
+		case <-timer.C:
+			return pausedFor, nil
+		case <-changed:
+			timer.Stop()
+		case <-c.cancelled:
+			timer.Stop()
+			return pausedFor, c.cancelCause()
+		case <-ctx.Done():
+			timer.Stop()
+			return pausedFor, ctx.Err()
+		}
+	}
+}
+
+func (c *control) isCancelled() bool {
+	select {
+	case <-c.cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func executedPrice(side string, buyPrice, sellPrice clients.NullFloat64) float64 {
+	if side == "sell" {
+		return sellPrice.Or(0)
+	}
+	return buyPrice.Or(0)
+}
+
+// beyondLimit reports whether price is worse than limit for side. A zero limit never triggers.
+func beyondLimit(side string, price, limit float64) bool {
+	if limit <= 0 {
+		return false
+	}
+	if side == "sell" {
+		return price < limit
+	}
+	return price > limit
+}
+
+func floorTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Floor(value*scale+1e-6) / scale
+}
+
+func findTradeSize(sizes []clients.TradeSize, pair clients.TokenPair) (clients.TradeSizeLimit, bool) {
+	for _, size := range sizes {
+		if size.TokenPair == pair {
+			return size.TradeSizeLimitQuoteToken, true
+		}
+	}
+	return clients.TradeSizeLimit{}, false
+}
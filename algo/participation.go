@@ -0,0 +1,301 @@
+package algo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// ErrDeadline is returned by Run when the deadline passed before the parent was filled.
+var ErrDeadline = errors.New("algo deadline reached")
+
+// ParticipationConfig describes a parent order worked from streaming price ladders.
+type ParticipationConfig struct {
+	TokenPair clients.TokenPair
+	// Side is buy or sell.
+	Side string
+	// Quantity is the parent quantity in base token.
+	Quantity float64
+	// MinClip and MaxClip bound each child in base token.
+	MinClip float64
+	MaxClip float64
+	// ThresholdBps is the largest acceptable impact of a clip versus the top ladder level.
+	// Clips are shrunk to stay within it and skipped when even MinClip exceeds it.
+	ThresholdBps float64
+	// MinInterval is the minimum time between two clips.
+	MinInterval time.Duration
+	// PriceLimit is the worst acceptable price: a ceiling for buys and a floor for sells.
+	PriceLimit float64
+	// Deadline stops the algorithm when reached. Zero means no deadline.
+	Deadline time.Time
+	// QuantityDecimals is the precision clips are rounded down to. Defaults to 8.
+	QuantityDecimals int
+	// KillSwitch, when set, cancels the algorithm as soon as it trips. Run then returns
+	// an error wrapping clients.ErrKillSwitchTripped.
+	KillSwitch *clients.KillSwitch
+	// OnProgress is called after every clip and when the algorithm finishes.
+	OnProgress func(ParticipationProgress)
+}
+
+// ParticipationProgress extends Progress with implementation shortfall against the
+// mid of the first ladder received.
+type ParticipationProgress struct {
+	Progress
+	ArrivalPrice float64
+	// ShortfallBps is positive when fills are worse than the arrival price.
+	ShortfallBps float64
+	// Shortfall is the same cost in quote token.
+	Shortfall float64
+	// Skipped counts ladders on which no clip was sent because of the threshold or limit.
+	Skipped int
+}
+
+// Participation sizes each clip from the live price ladder and executes it through
+// GetQuote and ExecuteQuote. Feed it ladders with Update, for example by passing
+// Update to SocketClient.OnPriceLadder.
+type Participation struct {
+	*control
+	client clients.Quoter
+	config ParticipationConfig
+
+	mu       sync.Mutex
+	latest   *clients.PriceLadder
+	updated  chan struct{}
+	progress ParticipationProgress
+}
+
+// NewParticipation validates config and creates a participation executor.
+func NewParticipation(client clients.Quoter, config ParticipationConfig) (*Participation, error) {
+	if config.Side != "buy" && config.Side != "sell" {
+		return nil, fmt.Errorf("participation: invalid side %q", config.Side)
+	}
+	if config.Quantity <= 0 || config.MaxClip <= 0 {
+		return nil, errors.New("participation: quantity and max clip must be positive")
+	}
+	if config.MinClip < 0 || config.MinClip > config.MaxClip {
+		return nil, errors.New("participation: min clip must be within [0, max clip]")
+	}
+	if config.ThresholdBps < 0 {
+		return nil, errors.New("participation: threshold must not be negative")
+	}
+	if config.QuantityDecimals <= 0 {
+		config.QuantityDecimals = defaultQuantityDecimals
+	}
+
+	return &Participation{
+		control: newControl(),
+		client:  client,
+		config:  config,
+		updated: make(chan struct{}, 1),
+		progress: ParticipationProgress{
+			Progress: Progress{State: StatePending, Target: config.Quantity},
+		},
+	}, nil
+}
+
+// Update hands the latest price ladder to the algorithm. Ladders for other pairs are ignored.
+func (algo *Participation) Update(ladder clients.PriceLadder) {
+	if ladder.TokenPair != algo.config.TokenPair {
+		return
+	}
+
+	algo.mu.Lock()
+	algo.latest = &ladder
+	if algo.progress.ArrivalPrice == 0 {
+		if mid, ok := ladder.Mid(); ok {
+			algo.progress.ArrivalPrice = mid
+		}
+	}
+	algo.mu.Unlock()
+
+	select {
+	case algo.updated <- struct{}{}:
+	default:
+	}
+}
+
+// Progress returns a snapshot of the algorithm's progress.
+func (algo *Participation) Progress() ParticipationProgress {
+	algo.mu.Lock()
+	defer algo.mu.Unlock()
+
+	progress := algo.progress
+	if progress.State == StateRunning && algo.Paused() {
+		progress.State = StatePaused
+	}
+	return progress
+}
+
+// Run works the parent order and blocks until it is filled, stopped, cancelled or ctx is done.
+func (algo *Participation) Run(ctx context.Context) (ParticipationProgress, error) {
+	if err := algo.start(); err != nil {
+		return algo.Progress(), err
+	}
+	stop := algo.watchKillSwitch(algo.config.KillSwitch)
+	defer stop()
+	algo.update(func(progress *ParticipationProgress) { progress.State = StateRunning })
+
+	var deadline <-chan time.Time
+	if !algo.config.Deadline.IsZero() {
+		timer := time.NewTimer(time.Until(algo.config.Deadline))
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	minStep := math.Pow(10, -float64(algo.config.QuantityDecimals))
+	var lastClip time.Time
+	for algo.Progress().Remaining() >= minStep {
+		select {
+		case <-algo.updated:
+		case <-algo.cancelled:
+			return algo.finish(StateCancelled, algo.cancelCause())
+		case <-deadline:
+			return algo.finish(StateStopped, ErrDeadline)
+		case <-ctx.Done():
+			return algo.finish(StateFailed, ctx.Err())
+		}
+
+		if algo.Paused() {
+			continue
+		}
+		if wait := algo.config.MinInterval - time.Since(lastClip); wait > 0 {
+			if _, err := algo.waitUntil(ctx, time.Now().Add(wait)); err != nil {
+				if algo.isCancelled() {
+					return algo.finish(StateCancelled, algo.cancelCause())
+				}
+				return algo.finish(StateFailed, err)
+			}
+		}
+
+		algo.mu.Lock()
+		ladder := algo.latest
+		algo.mu.Unlock()
+		if ladder == nil {
+			continue
+		}
+
+		clip, bound, ok := algo.nextClip(*ladder)
+		if !ok {
+			algo.update(func(progress *ParticipationProgress) { progress.Skipped++ })
+			continue
+		}
+
+		lastClip = time.Now()
+		result, err := clients.QuoteAndExecuteWith(ctx, algo.client, clients.QuoteRequest{
+			TokenPair:     algo.config.TokenPair,
+			Quantity:      clients.Quantity{Token: algo.config.TokenPair.BaseToken, Value: clip},
+			Side:          algo.config.Side,
+			ClientOrderId: clients.NewUUID(),
+		}, clients.AcceptancePolicy{LimitPrice: bound})
+		algo.update(func(progress *ParticipationProgress) { progress.Children++ })
+
+		switch {
+		case errors.Is(err, clients.ErrQuoteRejected), errors.Is(err, clients.ErrQuoteExpired),
+			errors.Is(err, clients.ErrQuoteNotFilled):
+			algo.update(func(progress *ParticipationProgress) { progress.Skipped++ })
+			continue
+		case err != nil:
+			return algo.finish(StateFailed, err)
+		}
+
+		algo.update(func(progress *ParticipationProgress) {
+			progress.addFill(clip, result.Price, result.Execution.FeeUSD)
+			algo.computeShortfall(progress)
+		})
+		algo.report()
+
+		if algo.isCancelled() {
+			return algo.finish(StateCancelled, algo.cancelCause())
+		}
+	}
+
+	return algo.finish(StateCompleted, nil)
+}
+
+// nextClip picks the largest clip up to MaxClip whose ladder impact is within the
+// threshold, and the worst price to accept for it.
+func (algo *Participation) nextClip(ladder clients.PriceLadder) (float64, float64, bool) {
+	config := algo.config
+	remaining := algo.Progress().Remaining()
+
+	clip := math.Min(math.Min(remaining, config.MaxClip), ladder.MaxQuantity())
+	candidates := []float64{clip}
+	for _, level := range ladder.Sorted() {
+		if level.Quantity < clip && level.Quantity >= config.MinClip {
+			candidates = append(candidates, level.Quantity)
+		}
+	}
+	if remaining < config.MinClip {
+		candidates = append(candidates, remaining)
+	} else if config.MinClip > 0 {
+		candidates = append(candidates, config.MinClip)
+	}
+
+	best := 0.0
+	for _, candidate := range candidates {
+		impact, ok := ladder.ImpactBps(config.Side, candidate)
+		if ok && impact <= config.ThresholdBps && candidate > best {
+			best = candidate
+		}
+	}
+	best = floorTo(best, config.QuantityDecimals)
+	if best <= 0 {
+		return 0, 0, false
+	}
+
+	price, _ := ladder.PriceFor(config.Side, best)
+	if beyondLimit(config.Side, price, config.PriceLimit) {
+		return 0, 0, false
+	}
+
+	bound := price * (1 + config.ThresholdBps/10000)
+	if config.Side == "sell" {
+		bound = price * (1 - config.ThresholdBps/10000)
+	}
+	if config.PriceLimit > 0 {
+		if config.Side == "sell" {
+			bound = math.Max(bound, config.PriceLimit)
+		} else {
+			bound = math.Min(bound, config.PriceLimit)
+		}
+	}
+	return best, bound, true
+}
+
+func (algo *Participation) computeShortfall(progress *ParticipationProgress) {
+	if progress.ArrivalPrice <= 0 || progress.Filled <= 0 {
+		return
+	}
+	diff := progress.AveragePrice - progress.ArrivalPrice
+	if algo.config.Side == "sell" {
+		diff = -diff
+	}
+	progress.ShortfallBps = diff / progress.ArrivalPrice * 10000
+	progress.Shortfall = diff * progress.Filled
+}
+
+func (algo *Participation) update(apply func(progress *ParticipationProgress)) {
+	algo.mu.Lock()
+	defer algo.mu.Unlock()
+	apply(&algo.progress)
+}
+
+func (algo *Participation) report() {
+	if algo.config.OnProgress != nil {
+		algo.config.OnProgress(algo.Progress())
+	}
+}
+
+func (algo *Participation) finish(state State, err error) (ParticipationProgress, error) {
+	algo.update(func(progress *ParticipationProgress) {
+		progress.State = state
+		progress.Err = err
+	})
+	algo.report()
+	return algo.Progress(), err
+}
@@ -0,0 +1,218 @@
+package algo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+const (
+	defaultSplitSteps       = 100
+	defaultSplitMaxChildren = 20
+)
+
+// SplitConfig tunes PlanSplit.
+type SplitConfig struct {
+	// Limit bounds every child's notional in quote token, as returned by GetTradeSizes.
+	// A zero Max means no upper bound.
+	Limit clients.TradeSizeLimit
+	// FeeBps is the expected net fee, used for the all-in cost.
+	FeeBps float64
+	// PerChildCost is a fixed cost in quote token charged per child, which makes the
+	// planner prefer fewer children when the ladder is flat.
+	PerChildCost float64
+	// MaxChildren caps the number of children. Defaults to 20.
+	MaxChildren int
+	// Steps is the resolution the target quantity is divided into. Defaults to 100.
+	Steps int
+	// QuantityDecimals is the precision child quantities are rounded down to. Defaults to 8.
+	QuantityDecimals int
+}
+
+// SplitChild is one planned child order.
+type SplitChild struct {
+	// Quantity in base token.
+	Quantity float64
+	// ExpectedPrice is the ladder price for Quantity.
+	ExpectedPrice float64
+	// Notional is Quantity times ExpectedPrice, in quote token.
+	Notional float64
+	// Fee is the expected fee in quote token.
+	Fee float64
+	// AllIn is what the child costs a buyer, Notional plus Fee, or nets a seller,
+	// Notional minus Fee.
+	AllIn float64
+}
+
+// SplitPlan is the cheapest split of a target quantity found by PlanSplit.
+type SplitPlan struct {
+	TokenPair clients.TokenPair
+	Side      string
+	Quantity  float64
+	Children  []SplitChild
+	// AllIn sums the children's AllIn.
+	AllIn float64
+	// AveragePrice is the expected all-in price per unit of base token.
+	AveragePrice float64
+}
+
+// PlanSplit finds the split of quantity into child orders with the best expected all-in
+// price, pricing each child independently off ladder and keeping every child within
+// config.Limit. It assumes the ladder's liquidity refreshes between children.
+func PlanSplit(side string, quantity float64, ladder clients.PriceLadder, config SplitConfig) (SplitPlan, error) {
+	if side != "buy" && side != "sell" {
+		return SplitPlan{}, fmt.Errorf("split: invalid side %q", side)
+	}
+	if quantity <= 0 {
+		return SplitPlan{}, errors.New("split: quantity must be positive")
+	}
+	if config.MaxChildren <= 0 {
+		config.MaxChildren = defaultSplitMaxChildren
+	}
+	if config.Steps <= 0 {
+		config.Steps = defaultSplitSteps
+	}
+	if config.QuantityDecimals <= 0 {
+		config.QuantityDecimals = defaultQuantityDecimals
+	}
+
+	steps := config.Steps
+	unit := quantity / float64(steps)
+	sign := 1.0
+	if side == "sell" {
+		sign = -1
+	}
+
+	// score[k] is the signed all-in cost of one child of k units; lower is better.
+	score := make([]float64, steps+1)
+	valid := make([]bool, steps+1)
+	for k := 1; k <= steps; k++ {
+		child, ok := priceChild(side, float64(k)*unit, ladder, config)
+		if ok {
+			score[k] = sign*child.AllIn + config.PerChildCost
+			valid[k] = true
+		}
+	}
+
+	// best[c][j] is the lowest score filling j units with exactly c children.
+	inf := math.Inf(1)
+	best := make([][]float64, config.MaxChildren+1)
+	choice := make([][]int, config.MaxChildren+1)
+	for c := range best {
+		best[c] = make([]float64, steps+1)
+		choice[c] = make([]int, steps+1)
+		for j := range best[c] {
+			best[c][j] = inf
+		}
+	}
+	best[0][0] = 0
+	for c := 1; c <= config.MaxChildren; c++ {
+		for j := 1; j <= steps; j++ {
+			for k := 1; k <= j; k++ {
+				if !valid[k] || best[c-1][j-k] == inf {
+					continue
+				}
+				if total := best[c-1][j-k] + score[k]; total < best[c][j] {
+					best[c][j] = total
+					choice[c][j] = k
+				}
+			}
+		}
+	}
+
+	children := 0
+	for c := 1; c <= config.MaxChildren; c++ {
+		if best[c][steps] < inf && (children == 0 || best[c][steps] < best[children][steps]) {
+			children = c
+		}
+	}
+	if children == 0 {
+		return SplitPlan{}, fmt.Errorf("split: no split of %v within trade size limits [%v, %v] and ladder depth %v",
+			quantity, config.Limit.Min, config.Limit.Max, ladder.MaxQuantity())
+	}
+
+	plan := SplitPlan{TokenPair: ladder.TokenPair, Side: side, Quantity: quantity}
+	allocated := 0.0
+	for c, j := children, steps; c > 0; c-- {
+		k := choice[c][j]
+		j -= k
+		size := floorTo(float64(k)*unit, config.QuantityDecimals)
+		if c == 1 {
+			size = floorTo(quantity-allocated, config.QuantityDecimals)
+		}
+		allocated += size
+
+		// Rounding to QuantityDecimals can leave a child empty or outside what the ladder
+		// and trade size limits allow, so the planned split cannot be sent.
+		child, ok := priceChild(side, size, ladder, config)
+		if !ok || size <= 0 {
+			return SplitPlan{}, fmt.Errorf("split: child of %v after rounding to %d decimals is outside trade size limits [%v, %v] or ladder depth %v",
+				size, config.QuantityDecimals, config.Limit.Min, config.Limit.Max, ladder.MaxQuantity())
+		}
+		plan.Children = append(plan.Children, child)
+		plan.AllIn += child.AllIn
+	}
+	plan.AveragePrice = plan.AllIn / quantity
+	return plan, nil
+}
+
+func priceChild(side string, quantity float64, ladder clients.PriceLadder, config SplitConfig) (SplitChild, bool) {
+	price, ok := ladder.PriceFor(side, quantity)
+	if !ok {
+		return SplitChild{}, false
+	}
+	notional := quantity * price
+	if notional < config.Limit.Min || (config.Limit.Max > 0 && notional > config.Limit.Max) {
+		return SplitChild{}, false
+	}
+
+	fee := notional * config.FeeBps / 10000
+	allIn := notional + fee
+	if side == "sell" {
+		allIn = notional - fee
+	}
+	return SplitChild{Quantity: quantity, ExpectedPrice: price, Notional: notional, Fee: fee, AllIn: allIn}, true
+}
+
+// ExecuteSplit sends each child of plan as a FOK limit order at its expected price,
+// allowing slippageBps. It stops at the first child that is not filled and returns
+// ErrPriceLimit with the progress so far.
+func ExecuteSplit(ctx context.Context, client clients.Orderer, plan SplitPlan, slippageBps float64) (Progress, error) {
+	progress := Progress{State: StateRunning, Target: plan.Quantity}
+
+	for _, child := range plan.Children {
+		if err := ctx.Err(); err != nil {
+			progress.State, progress.Err = StateFailed, err
+			return progress, err
+		}
+
+		order, err := client.PlaceOrder(clients.OrderRequest{
+			TokenPair:     plan.TokenPair,
+			Quantity:      clients.Quantity{Token: plan.TokenPair.BaseToken, Value: child.Quantity},
+			Side:          plan.Side,
+			OrderType:     "limit",
+			TimeInForce:   "fok",
+			LimitPrice:    child.ExpectedPrice,
+			SlippageBps:   slippageBps,
+			ClientOrderId: clients.NewUUID(),
+		})
+		progress.Children++
+		progress.LastOrder = &order
+		if err != nil {
+			progress.State, progress.Err = StateFailed, err
+			return progress, err
+		}
+		if !order.IsFilled {
+			progress.State, progress.Err = StateStopped, ErrPriceLimit
+			return progress, ErrPriceLimit
+		}
+
+		progress.addFill(child.Quantity, executedPrice(plan.Side, order.BuyPrice, order.SellPrice), order.FeeUSD)
+	}
+
+	progress.State = StateCompleted
+	return progress, nil
+}
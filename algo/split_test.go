@@ -0,0 +1,110 @@
+package algo_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/falconxio/falconx-go/algo"
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// splitLadder prices up to 1 BTC at 100/99, interpolating to 101/98 at 2 BTC and to
+// 110/90 at 5 BTC.
+var splitLadder = clients.PriceLadder{
+	TokenPair: btcUSD,
+	Levels: []clients.PriceLevel{
+		{Quantity: 5, BuyPrice: 110, SellPrice: 90},
+		{Quantity: 1, BuyPrice: 100, SellPrice: 99},
+		{Quantity: 2, BuyPrice: 101, SellPrice: 98},
+	},
+}
+
+func TestPlanSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		side     string
+		quantity float64
+		config   algo.SplitConfig
+		// children are the planned child quantities, and allIn their total all-in.
+		children []float64
+		allIn    float64
+		err      string
+	}{
+		{"within the first level", "buy", 1, algo.SplitConfig{Steps: 4}, []float64{1}, 100, ""},
+		{"split at the first level", "buy", 2, algo.SplitConfig{Steps: 4}, []float64{1, 1}, 200, ""},
+		{"per child cost keeps one child", "buy", 2, algo.SplitConfig{Steps: 4, PerChildCost: 5}, []float64{2}, 202, ""},
+		{"sell with fees", "sell", 2, algo.SplitConfig{Steps: 4, FeeBps: 10}, []float64{1, 1}, 197.802, ""},
+		{"trade size maximum", "buy", 2, algo.SplitConfig{Steps: 4, Limit: clients.TradeSizeLimit{Max: 60}},
+			[]float64{0.5, 0.5, 0.5, 0.5}, 200, ""},
+		{"too few children for the maximum", "buy", 2,
+			algo.SplitConfig{Steps: 4, MaxChildren: 3, Limit: clients.TradeSizeLimit{Max: 60}}, nil, 0, "no split"},
+		{"beyond the ladder depth", "buy", 6, algo.SplitConfig{Steps: 6, MaxChildren: 1}, nil, 0, "no split"},
+		{"empty child after rounding", "buy", 0.001, algo.SplitConfig{Steps: 1, QuantityDecimals: 2}, nil, 0,
+			"child of 0 after rounding"},
+		{"child below the minimum after rounding", "buy", 0.019,
+			algo.SplitConfig{Steps: 1, QuantityDecimals: 2, Limit: clients.TradeSizeLimit{Min: 1.5}}, nil, 0,
+			"child of 0.01 after rounding"},
+		{"invalid side", "two_way", 1, algo.SplitConfig{}, nil, 0, "invalid side"},
+		{"zero quantity", "buy", 0, algo.SplitConfig{}, nil, 0, "quantity must be positive"},
+	}
+	for _, test := range tests {
+		plan, err := algo.PlanSplit(test.side, test.quantity, splitLadder, test.config)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: err = %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		var children []float64
+		for _, child := range plan.Children {
+			children = append(children, child.Quantity)
+		}
+		if !reflect.DeepEqual(children, test.children) {
+			t.Errorf("%s: children = %v, want %v", test.name, children, test.children)
+		}
+		if math.Abs(plan.AllIn-test.allIn) > 1e-9 {
+			t.Errorf("%s: all-in = %v, want %v", test.name, plan.AllIn, test.allIn)
+		}
+		if math.Abs(plan.AveragePrice-test.allIn/test.quantity) > 1e-9 {
+			t.Errorf("%s: average price = %v, want %v", test.name, plan.AveragePrice, test.allIn/test.quantity)
+		}
+	}
+}
+
+func TestExecuteSplit(t *testing.T) {
+	plan, err := algo.PlanSplit("buy", 2, splitLadder, algo.SplitConfig{Steps: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := fillingClient(100)
+	progress, err := algo.ExecuteSplit(context.Background(), client, plan, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.State != algo.StateCompleted || progress.Filled != 2 || progress.Children != 2 {
+		t.Errorf("progress = %s, filled %v over %d children; want completed, 2 over 2", progress.State, progress.Filled, progress.Children)
+	}
+	for _, call := range client.PlaceOrderCalls() {
+		order := call.OrderParams
+		if order.OrderType != "limit" || order.TimeInForce != "fok" || order.LimitPrice != 100 || order.SlippageBps != 5 {
+			t.Errorf("child order = %+v, want FOK limit at 100 with 5 bps slippage", order)
+		}
+	}
+
+	// The first child is not filled above its expected price, so the split stops.
+	progress, err = algo.ExecuteSplit(context.Background(), fillingClient(101), plan, 5)
+	if !errors.Is(err, algo.ErrPriceLimit) || progress.State != algo.StateStopped || progress.Children != 1 || progress.Filled != 0 {
+		t.Errorf("progress = %s, filled %v over %d children, err %v; want stopped after one unfilled child",
+			progress.State, progress.Filled, progress.Children, err)
+	}
+}
@@ -0,0 +1,319 @@
+package algo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// TWAPConfig describes a TWAP parent order.
+type TWAPConfig struct {
+	TokenPair clients.TokenPair
+	// Side is buy or sell.
+	Side string
+	// Quantity is the parent quantity in base token.
+	Quantity float64
+	// Duration is the time the parent is worked over.
+	Duration time.Duration
+	// Slices is the planned number of child orders. It is adjusted so that every child
+	// stays within the pair's GetTradeSizes limits.
+	Slices int
+	// Randomization in [0, 1] jitters each child's send time and size by up to
+	// ±Randomization/2 of the interval and of the even child size.
+	Randomization float64
+	// OrderType is market (default) or limit. Limit children are sent FOK at PriceLimit
+	// with SlippageBps.
+	OrderType   string
+	SlippageBps float64
+	// PriceLimit is the worst acceptable price: a ceiling for buys and a floor for sells.
+	// The algorithm stops once a child fills beyond it or a FOK child is not filled.
+	PriceLimit float64
+	// ReferencePrice converts trade size limits, which are in quote token, to base token.
+	// When zero it is taken from a quote at start.
+	ReferencePrice float64
+	// QuantityDecimals is the precision child quantities are rounded down to. Defaults to 8.
+	QuantityDecimals int
+	// KillSwitch, when set, cancels the algorithm as soon as it trips. Run then returns
+	// an error wrapping clients.ErrKillSwitchTripped.
+	KillSwitch *clients.KillSwitch
+	// OnProgress is called after every child order and when the algorithm finishes.
+	OnProgress func(Progress)
+}
+
+// TWAP slices a parent order into child PlaceOrder calls spread evenly over a duration.
+// It can be paused, resumed and cancelled from any goroutine while Run is executing.
+type TWAP struct {
+	*control
+	client clients.FalconX
+	config TWAPConfig
+	random *rand.Rand
+
+	mu       sync.Mutex
+	progress Progress
+}
+
+// NewTWAP validates config and creates a TWAP executor.
+func NewTWAP(client clients.FalconX, config TWAPConfig) (*TWAP, error) {
+	if config.Side != "buy" && config.Side != "sell" {
+		return nil, fmt.Errorf("twap: invalid side %q", config.Side)
+	}
+	if config.Quantity <= 0 {
+		return nil, errors.New("twap: quantity must be positive")
+	}
+	if config.Duration <= 0 || config.Slices <= 0 {
+		return nil, errors.New("twap: duration and slices must be positive")
+	}
+	if config.Randomization < 0 || config.Randomization > 1 {
+		return nil, errors.New("twap: randomization must be within [0, 1]")
+	}
+	if config.OrderType == "" {
+		config.OrderType = "market"
+	}
+	if config.OrderType != "market" && config.OrderType != "limit" {
+		return nil, fmt.Errorf("twap: invalid order type %q", config.OrderType)
+	}
+	if config.OrderType == "limit" && config.PriceLimit <= 0 {
+		return nil, errors.New("twap: limit orders require a price limit")
+	}
+	if config.QuantityDecimals <= 0 {
+		config.QuantityDecimals = defaultQuantityDecimals
+	}
+
+	return &TWAP{
+		control:  newControl(),
+		client:   client,
+		config:   config,
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		progress: Progress{State: StatePending, Target: config.Quantity},
+	}, nil
+}
+
+// Progress returns a snapshot of the algorithm's progress.
+func (twap *TWAP) Progress() Progress {
+	twap.mu.Lock()
+	defer twap.mu.Unlock()
+
+	progress := twap.progress
+	if progress.State == StateRunning && twap.Paused() {
+		progress.State = StatePaused
+	}
+	return progress
+}
+
+// Run works the parent order and blocks until it is filled, stopped, cancelled or ctx is done.
+func (twap *TWAP) Run(ctx context.Context) (Progress, error) {
+	if err := twap.start(); err != nil {
+		return twap.Progress(), err
+	}
+	stop := twap.watchKillSwitch(twap.config.KillSwitch)
+	defer stop()
+	config := twap.config
+
+	sizes, err := twap.client.GetTradeSizes()
+	if err != nil {
+		return twap.finish(StateFailed, err)
+	}
+	limit, hasLimit := findTradeSize(sizes, config.TokenPair)
+
+	price := config.ReferencePrice
+	if price <= 0 {
+		price, err = twap.referencePrice(config.Quantity / float64(config.Slices))
+		if err != nil {
+			return twap.finish(StateFailed, err)
+		}
+	}
+
+	slices, err := planSlices(config.Quantity, price, config.Slices, limit, hasLimit)
+	if err != nil {
+		return twap.finish(StateFailed, err)
+	}
+	interval := config.Duration / time.Duration(slices)
+	minStep := math.Pow(10, -float64(config.QuantityDecimals))
+
+	twap.update(func(progress *Progress) { progress.State = StateRunning })
+
+	start := time.Now()
+	var shift time.Duration
+	for i := 0; twap.Progress().Remaining() >= minStep; i++ {
+		scheduled := start.Add(shift + time.Duration(i)*interval + twap.jitter(interval))
+		if scheduled.Before(start) {
+			scheduled = start
+		}
+		paused, err := twap.waitUntil(ctx, scheduled)
+		shift += paused
+		if twap.isCancelled() {
+			return twap.finish(StateCancelled, twap.cancelCause())
+		}
+		if err != nil {
+			return twap.finish(StateFailed, err)
+		}
+
+		quantity := twap.childQuantity(i, slices, price, limit, hasLimit)
+		if quantity < minStep {
+			break
+		}
+
+		order, err := twap.client.PlaceOrder(clients.OrderRequest{
+			TokenPair:     config.TokenPair,
+			Quantity:      clients.Quantity{Token: config.TokenPair.BaseToken, Value: quantity},
+			Side:          config.Side,
+			OrderType:     config.OrderType,
+			TimeInForce:   timeInForce(config.OrderType),
+			LimitPrice:    limitPrice(config.OrderType, config.PriceLimit),
+			SlippageBps:   slippage(config.OrderType, config.SlippageBps),
+			ClientOrderId: clients.NewUUID(),
+		})
+		twap.update(func(progress *Progress) {
+			progress.Children++
+			progress.LastOrder = &order
+		})
+		if err != nil {
+			return twap.finish(StateFailed, err)
+		}
+		if !order.IsFilled {
+			if config.OrderType == "limit" {
+				return twap.finish(StateStopped, ErrPriceLimit)
+			}
+			return twap.finish(StateFailed, fmt.Errorf("twap: market child %s not filled", order.FxQuoteId))
+		}
+
+		fillPrice := executedPrice(config.Side, order.BuyPrice, order.SellPrice)
+		twap.update(func(progress *Progress) { progress.addFill(quantity, fillPrice, order.FeeUSD) })
+		twap.report()
+		if fillPrice > 0 {
+			price = fillPrice
+		}
+
+		if beyondLimit(config.Side, fillPrice, config.PriceLimit) {
+			return twap.finish(StateStopped, ErrPriceLimit)
+		}
+		if twap.isCancelled() {
+			return twap.finish(StateCancelled, twap.cancelCause())
+		}
+	}
+
+	return twap.finish(StateCompleted, nil)
+}
+
+func (twap *TWAP) referencePrice(quantity float64) (float64, error) {
+	config := twap.config
+	quote, err := twap.client.GetQuote(clients.QuoteRequest{
+		TokenPair:     config.TokenPair,
+		Quantity:      clients.Quantity{Token: config.TokenPair.BaseToken, Value: floorTo(quantity, config.QuantityDecimals)},
+		Side:          config.Side,
+		ClientOrderId: clients.NewUUID(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	price := executedPrice(config.Side, quote.BuyPrice, quote.SellPrice)
+	if price <= 0 {
+		return 0, fmt.Errorf("twap: no %s price in reference quote %s", config.Side, quote.FxQuoteId)
+	}
+	return price, nil
+}
+
+// planSlices adjusts the number of slices so an even child stays within the trade size limits.
+func planSlices(quantity, price float64, slices int, limit clients.TradeSizeLimit, hasLimit bool) (int, error) {
+	if !hasLimit {
+		return slices, nil
+	}
+	notional := quantity * price
+	if notional < limit.Min {
+		return 0, fmt.Errorf("twap: parent notional %v below minimum trade size %v", notional, limit.Min)
+	}
+	if limit.Min > 0 && notional/float64(slices) < limit.Min {
+		slices = int(math.Max(1, math.Floor(notional/limit.Min)))
+	}
+	if limit.Max > 0 && notional/float64(slices) > limit.Max {
+		slices = int(math.Ceil(notional / limit.Max))
+	}
+	return slices, nil
+}
+
+// childQuantity sizes child i, randomized and clamped to the trade size limits. The last
+// child, or one that would leave less than the minimum behind, takes the whole remainder.
+func (twap *TWAP) childQuantity(i, slices int, price float64, limit clients.TradeSizeLimit, hasLimit bool) float64 {
+	config := twap.config
+	remaining := twap.Progress().Remaining()
+
+	left := slices - i
+	if left <= 1 {
+		return twap.clamp(remaining, remaining, price, limit, hasLimit)
+	}
+
+	quantity := remaining / float64(left)
+	quantity *= 1 + config.Randomization*(twap.random.Float64()-0.5)
+	return twap.clamp(quantity, remaining, price, limit, hasLimit)
+}
+
+func (twap *TWAP) clamp(quantity, remaining, price float64, limit clients.TradeSizeLimit, hasLimit bool) float64 {
+	if hasLimit && price > 0 {
+		minQty, maxQty := limit.Min/price, math.Inf(1)
+		if limit.Max > 0 {
+			maxQty = limit.Max / price
+		}
+		quantity = math.Max(quantity, minQty)
+		if remaining-quantity < minQty {
+			quantity = remaining
+		}
+		quantity = math.Min(quantity, maxQty)
+	}
+	quantity = math.Min(quantity, remaining)
+	return floorTo(quantity, twap.config.QuantityDecimals)
+}
+
+func (twap *TWAP) jitter(interval time.Duration) time.Duration {
+	if twap.config.Randomization == 0 {
+		return 0
+	}
+	return time.Duration(float64(interval) * twap.config.Randomization * (twap.random.Float64() - 0.5))
+}
+
+func (twap *TWAP) update(apply func(progress *Progress)) {
+	twap.mu.Lock()
+	defer twap.mu.Unlock()
+	apply(&twap.progress)
+}
+
+func (twap *TWAP) report() {
+	if twap.config.OnProgress != nil {
+		twap.config.OnProgress(twap.Progress())
+	}
+}
+
+func (twap *TWAP) finish(state State, err error) (Progress, error) {
+	twap.update(func(progress *Progress) {
+		progress.State = state
+		progress.Err = err
+	})
+	twap.report()
+	return twap.Progress(), err
+}
+
+func timeInForce(orderType string) string {
+	if orderType == "limit" {
+		return "fok"
+	}
+	return ""
+}
+
+func limitPrice(orderType string, price float64) float64 {
+	if orderType == "limit" {
+		return price
+	}
+	return 0
+}
+
+func slippage(orderType string, bps float64) float64 {
+	if orderType == "limit" {
+		return bps
+	}
+	return 0
+}
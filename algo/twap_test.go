@@ -0,0 +1,232 @@
+package algo_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/algo"
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/clients/mocks"
+)
+
+var btcUSD = clients.TokenPair{BaseToken: "BTC", QuoteToken: "USD"}
+
+// fillingClient fills every order at price and has the given BTC/USD trade size limits.
+func fillingClient(price float64, sizes ...clients.TradeSize) *mocks.FalconXMock {
+	return &mocks.FalconXMock{
+		GetTradeSizesFunc: func() ([]clients.TradeSize, error) {
+			return sizes, nil
+		},
+		PlaceOrderFunc: func(orderParams clients.OrderRequest) (clients.OrderResponse, error) {
+			return clients.OrderResponse{
+				FxQuoteId:    orderParams.ClientOrderId,
+				IsFilled:     orderParams.OrderType == "market" || orderParams.LimitPrice >= price,
+				BuyPrice:     clients.NewNullFloat64(price),
+				SideExecuted: clients.NewNullSide(orderParams.Side),
+				FeeUSD:       1,
+			}, nil
+		},
+	}
+}
+
+func TestTWAPSlicesEvenly(t *testing.T) {
+	client := fillingClient(100)
+	twap, err := algo.NewTWAP(client, algo.TWAPConfig{
+		TokenPair:      btcUSD,
+		Side:           "buy",
+		Quantity:       1,
+		Duration:       40 * time.Millisecond,
+		Slices:         4,
+		ReferencePrice: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress, err := twap.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.State != algo.StateCompleted || progress.Filled != 1 || progress.Children != 4 {
+		t.Errorf("progress = %s, filled %v over %d children; want completed, 1 over 4", progress.State, progress.Filled, progress.Children)
+	}
+	if progress.AveragePrice != 100 || progress.FeesUSD != 4 {
+		t.Errorf("average price %v, fees %v; want 100 and 4", progress.AveragePrice, progress.FeesUSD)
+	}
+	for _, call := range client.PlaceOrderCalls() {
+		if call.OrderParams.Quantity.Value != 0.25 {
+			t.Errorf("child quantity %v, want 0.25", call.OrderParams.Quantity.Value)
+		}
+	}
+	if len(client.GetQuoteCalls()) != 0 {
+		t.Error("reference quote requested although ReferencePrice was set")
+	}
+
+	if _, err := twap.Run(context.Background()); err != algo.ErrAlreadyStarted {
+		t.Errorf("second Run = %v, want ErrAlreadyStarted", err)
+	}
+}
+
+func TestTWAPPauseResume(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	client := fillingClient(100)
+	paused := make(chan struct{})
+	var twap *algo.TWAP
+	twap, err := algo.NewTWAP(client, algo.TWAPConfig{
+		TokenPair:      btcUSD,
+		Side:           "buy",
+		Quantity:       1,
+		Duration:       4 * interval,
+		Slices:         4,
+		ReferencePrice: 100,
+		OnProgress: func(progress algo.Progress) {
+			if progress.Children == 1 && progress.State == algo.StateRunning {
+				twap.Pause()
+				close(paused)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan algo.Progress)
+	go func() {
+		progress, _ := twap.Run(context.Background())
+		done <- progress
+	}()
+
+	<-paused
+	time.Sleep(5 * interval)
+	if n := len(client.PlaceOrderCalls()); n != 1 {
+		t.Errorf("%d children sent while paused, want 1", n)
+	}
+	if state := twap.Progress().State; state != algo.StatePaused {
+		t.Errorf("state = %s, want paused", state)
+	}
+
+	resumed := time.Now()
+	twap.Resume()
+	progress := <-done
+	if progress.State != algo.StateCompleted || progress.Children != 4 {
+		t.Errorf("progress = %s with %d children, want completed with 4", progress.State, progress.Children)
+	}
+	// The remaining children keep their spacing instead of catching up at once.
+	if elapsed := time.Since(resumed); elapsed < 2*interval {
+		t.Errorf("remaining children sent within %v of resuming, want at least %v", elapsed, 2*interval)
+	}
+}
+
+func TestTWAPCancelWhilePaused(t *testing.T) {
+	client := fillingClient(100)
+	twap, err := algo.NewTWAP(client, algo.TWAPConfig{
+		TokenPair:      btcUSD,
+		Side:           "buy",
+		Quantity:       1,
+		Duration:       time.Second,
+		Slices:         4,
+		ReferencePrice: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	twap.Pause()
+
+	done := make(chan error)
+	go func() {
+		_, err := twap.Run(context.Background())
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	twap.Cancel()
+
+	if err := <-done; err != algo.ErrCancelled {
+		t.Errorf("Run = %v, want ErrCancelled", err)
+	}
+	if progress := twap.Progress(); progress.State != algo.StateCancelled || progress.Children != 0 {
+		t.Errorf("progress = %s with %d children, want cancelled with none", progress.State, progress.Children)
+	}
+}
+
+func TestTWAPKillSwitch(t *testing.T) {
+	killSwitch, err := clients.NewKillSwitch("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	killSwitch.Trip("test")
+	twap, err := algo.NewTWAP(fillingClient(100), algo.TWAPConfig{
+		TokenPair:      btcUSD,
+		Side:           "buy",
+		Quantity:       1,
+		Duration:       time.Second,
+		Slices:         4,
+		ReferencePrice: 100,
+		KillSwitch:     killSwitch,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := twap.Run(context.Background()); !errors.Is(err, clients.ErrKillSwitchTripped) {
+		t.Errorf("Run = %v, want ErrKillSwitchTripped", err)
+	}
+}
+
+func TestTWAPLimitStops(t *testing.T) {
+	client := fillingClient(101)
+	twap, err := algo.NewTWAP(client, algo.TWAPConfig{
+		TokenPair:      btcUSD,
+		Side:           "buy",
+		Quantity:       1,
+		Duration:       40 * time.Millisecond,
+		Slices:         4,
+		OrderType:      "limit",
+		PriceLimit:     100,
+		ReferencePrice: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress, err := twap.Run(context.Background())
+	if err != algo.ErrPriceLimit || progress.State != algo.StateStopped {
+		t.Errorf("Run = %s, %v; want stopped with ErrPriceLimit", progress.State, err)
+	}
+	calls := client.PlaceOrderCalls()
+	if len(calls) != 1 || calls[0].OrderParams.TimeInForce != "fok" || calls[0].OrderParams.LimitPrice != 100 {
+		t.Errorf("children = %+v, want one FOK child at 100", calls)
+	}
+}
+
+func TestTWAPRespectsTradeSizes(t *testing.T) {
+	client := fillingClient(100, clients.TradeSize{
+		TokenPair:                btcUSD,
+		TradeSizeLimitQuoteToken: clients.TradeSizeLimit{Min: 10, Max: 30},
+	})
+	twap, err := algo.NewTWAP(client, algo.TWAPConfig{
+		TokenPair:      btcUSD,
+		Side:           "buy",
+		Quantity:       1,
+		Duration:       40 * time.Millisecond,
+		Slices:         2,
+		ReferencePrice: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress, err := twap.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.Children != 4 || math.Abs(progress.Filled-1) > 1e-9 {
+		t.Errorf("filled %v over %d children, want 1 over 4", progress.Filled, progress.Children)
+	}
+	for _, call := range client.PlaceOrderCalls() {
+		if notional := call.OrderParams.Quantity.Value * 100; notional < 10 || notional > 30 {
+			t.Errorf("child notional %v outside [10, 30]", notional)
+		}
+	}
+}
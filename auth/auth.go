@@ -0,0 +1,95 @@
+// Package auth implements FalconX request authentication, shared by the REST and
+// socket.io clients.
+//
+// A request is signed by computing the HMAC-SHA256 of its prehash, keyed with the
+// base64-decoded API secret, and sending the base64 encoded result as FX-ACCESS-SIGN
+// together with the key, passphrase and timestamp headers. The prehash is the
+// timestamp, the upper-case method, the request path and the JSON body concatenated
+// without separators:
+//
+//	1561636761POST/v1/quotes{"token_pair":...}
+//
+// The socket.io handshake is signed like a GET of /socket.io/ with no body.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header names sent with every authenticated request.
+const (
+	HeaderKey         = "FX-ACCESS-KEY"
+	HeaderSign        = "FX-ACCESS-SIGN"
+	HeaderTimestamp   = "FX-ACCESS-TIMESTAMP"
+	HeaderPassphrase  = "FX-ACCESS-PASSPHRASE"
+	HeaderContentType = "Content-Type"
+
+	contentTypeJSON = "application/json"
+)
+
+// SocketMethod and SocketPath are signed for the socket.io handshake.
+const (
+	SocketMethod = "GET"
+	SocketPath   = "/socket.io/"
+)
+
+// ErrBadSecret is returned when the API secret is not valid base64.
+var ErrBadSecret = errors.New("auth: secret is not valid base64")
+
+// Timestamp formats t as FX-ACCESS-TIMESTAMP, in Unix seconds.
+func Timestamp(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// Prehash returns the message signed for a request. path includes the query string, if
+// any, and body is the exact JSON sent, or empty.
+func Prehash(timestamp, method, path, body string) string {
+	return timestamp + strings.ToUpper(method) + path + body
+}
+
+// SocketPrehash returns the message signed for the socket.io handshake.
+func SocketPrehash(timestamp string) string {
+	return Prehash(timestamp, SocketMethod, SocketPath, "")
+}
+
+// DecodeSecret decodes a base64 API secret into the HMAC key.
+func DecodeSecret(secret string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, ErrBadSecret
+	}
+	return key, nil
+}
+
+// Sign returns the base64 encoded HMAC-SHA256 of message keyed with key.
+func Sign(message string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignWithSecret decodes secret and signs message with it.
+func SignWithSecret(message, secret string) (string, error) {
+	key, err := DecodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return Sign(message, key), nil
+}
+
+// Headers returns the header set of a signed request.
+func Headers(apiKey, passphrase, timestamp, signature string) map[string]string {
+	return map[string]string{
+		HeaderContentType: contentTypeJSON,
+		HeaderKey:         apiKey,
+		HeaderPassphrase:  passphrase,
+		HeaderTimestamp:   timestamp,
+		HeaderSign:        signature,
+	}
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+const testSecret = "c2VjcmV0LWtleS1mb3ItdGVzdHM=" // "secret-key-for-tests"
+
+func TestPrehash(t *testing.T) {
+	tests := []struct {
+		name                          string
+		timestamp, method, path, body string
+		want                          string
+	}{
+		{"get", "1561636761", "GET", "/v1/pairs", "", "1561636761GET/v1/pairs"},
+		{"lower-case method", "1561636761", "get", "/v1/pairs", "", "1561636761GET/v1/pairs"},
+		{"post with body", "1561636761", "POST", "/v1/quotes", `{"side":"buy"}`, `1561636761POST/v1/quotes{"side":"buy"}`},
+	}
+	for _, test := range tests {
+		if got := Prehash(test.timestamp, test.method, test.path, test.body); got != test.want {
+			t.Errorf("%s: Prehash = %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	if got, want := SocketPrehash("1561636761"), "1561636761GET/socket.io/"; got != want {
+		t.Errorf("SocketPrehash = %q, want %q", got, want)
+	}
+}
+
+func TestSignKnownAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		message string
+		want    string
+	}{
+		{
+			// RFC 4231 test case 2.
+			name:    "rfc4231",
+			secret:  "SmVmZQ==",
+			message: "what do ya want for nothing?",
+			want:    "W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM=",
+		},
+		{
+			name:    "rest get",
+			secret:  testSecret,
+			message: Prehash("1561636761", "GET", "/v1/pairs", ""),
+			want:    "0xPCueTORt4Mz4jcLBRZvUslJeemfqIOUpmfRHAk8OU=",
+		},
+		{
+			name:    "rest get with path parameter",
+			secret:  testSecret,
+			message: Prehash("1561636761", "GET", "/v1/quotes/00c884b056f949338788dfb59e495377", ""),
+			want:    "MP8lI3/Qpr1K74TbNHwwChJsuoTnKnFr4QxTp4UaEZs=",
+		},
+		{
+			name:   "rest post",
+			secret: testSecret,
+			message: Prehash("1561636761", "POST", "/v1/quotes",
+				`{"token_pair":{"base_token":"BTC","quote_token":"USD"},"quantity":{"token":"BTC","value":"10"},"side":"buy"}`),
+			want: "N8THJ2zCMR/JJsUQOEWZA/8Qk9NrkyGbTWT2WElo8CU=",
+		},
+		{
+			name:    "socket handshake",
+			secret:  testSecret,
+			message: SocketPrehash("1561636761"),
+			want:    "aCfyZ+Y9lBUsJx7f50egW/dPzROo2YY9eUhxsjUk9so=",
+		},
+	}
+	for _, test := range tests {
+		got, err := SignWithSecret(test.message, test.secret)
+		if err != nil {
+			t.Errorf("%s: SignWithSecret: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: signature = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSignWithBadSecret(t *testing.T) {
+	if _, err := SignWithSecret("message", "not base64!"); err != ErrBadSecret {
+		t.Errorf("SignWithSecret error = %v, want ErrBadSecret", err)
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	at := time.Date(2019, 6, 27, 11, 59, 21, 875725000, time.UTC)
+	if got, want := Timestamp(at), "1561636761"; got != want {
+		t.Errorf("Timestamp = %s, want %s", got, want)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	got := Headers("key", "passphrase", "1561636761", "signature")
+	want := map[string]string{
+		"Content-Type":         "application/json",
+		"FX-ACCESS-KEY":        "key",
+		"FX-ACCESS-PASSPHRASE": "passphrase",
+		"FX-ACCESS-TIMESTAMP":  "1561636761",
+		"FX-ACCESS-SIGN":       "signature",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Headers = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("header %s = %q, want %q", name, got[name], value)
+		}
+	}
+}
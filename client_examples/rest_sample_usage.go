@@ -9,17 +9,23 @@ import (
 )
 
 func RunRestExamples(apiKey string, secret string, passPhrase string, host string) {
-
-	if len(host) == 0 {
-		host = "https://qa.falconxdev.com"
-	}
-
-	client := clients.NewRestClient(clients.RestClientConfig{
+	RunRestExamplesWithConfig(clients.RestClientConfig{
 		BaseURL:    host,
 		APIKey:     apiKey,
 		Passphrase: passPhrase,
 		Secret:     secret,
 	})
+}
+
+// RunRestExamplesWithConfig runs the REST examples with a client configuration, such as
+// one built from a config profile, whose Signer may replace the secret.
+func RunRestExamplesWithConfig(config clients.RestClientConfig) {
+
+	if len(config.BaseURL) == 0 {
+		config.BaseURL = "https://qa.falconxdev.com"
+	}
+
+	client := clients.NewRestClient(config)
 
 	tokenPair := clients.TokenPair{BaseToken: "BTC", QuoteToken: "USD"}
 	quantity := clients.Quantity{Token: "BTC", Value: 0.001}
@@ -30,7 +36,7 @@ func RunRestExamples(apiKey string, secret string, passPhrase string, host strin
 	fmt.Printf("\n\n Quote Resp: \n%s, err: %+v", quoteResponseJson, err)
 
 	fxQuoteId := quoteResponse.FxQuoteId
-	limit_price := quoteResponse.BuyPrice + 5
+	limit_price := quoteResponse.BuyPrice.Float64 + 5
 	side := quoteResponse.SideRequested
 
 	// Quote Not Executed Here
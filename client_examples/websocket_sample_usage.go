@@ -23,22 +23,26 @@ const (
 )
 
 func RunWebSocketExamples(apiKey string, secret string, passphrase string, host string) {
+	RunWebSocketExamplesWithConfig(clients.SocketClientConfig{
+		Host:       host,
+		Secret:     secret,
+		APIKey:     apiKey,
+		Passphrase: passphrase,
+	})
+}
 
-	if len(host) == 0 {
-		host = "ws.falconx.io"
+// RunWebSocketExamplesWithConfig runs the WebSocket examples with a client
+// configuration, such as one built from a config profile, whose Signer may replace the
+// secret.
+func RunWebSocketExamplesWithConfig(config clients.SocketClientConfig) {
+
+	if len(config.Host) == 0 {
+		config.Host = "ws.falconx.io"
 	}
 
 	streamingNamespace := "/streaming"
 
-	client := clients.NewSocketClient(
-		clients.SocketClientConfig{
-			Host:       host,
-			Secret:     secret,
-			APIKey:     apiKey,
-			Passphrase: passphrase,
-		},
-		streamingNamespace,
-	)
+	client := clients.NewSocketClient(config, streamingNamespace)
 
 	err := client.Connect()
 	if err != nil {
@@ -0,0 +1,108 @@
+package clients
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSkewWarning = 5 * time.Second
+	// clockResolution is the precision of the HTTP Date header. Offsets that differ by
+	// less than this from the current one are noise and are not applied.
+	clockResolution = time.Second
+)
+
+// ClockConfig configures a Clock.
+type ClockConfig struct {
+	// WarnThreshold is the skew above which OnSkew is called. Defaults to 5s.
+	WarnThreshold time.Duration
+	// OnSkew is called whenever a newly measured offset exceeds WarnThreshold. Defaults
+	// to logging a warning.
+	OnSkew func(offset time.Duration)
+	// Now reads the local clock. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Clock is the local clock corrected by its measured offset from FalconX's clock, used
+// for FX-ACCESS-TIMESTAMP. RestClient measures the offset from the Date header of every
+// response; share the same Clock with SocketClient so the handshake uses it too. A nil
+// Clock reads the local clock unchanged.
+type Clock struct {
+	config ClockConfig
+
+	mu     sync.Mutex
+	offset time.Duration
+	synced time.Time
+}
+
+// NewClock creates a Clock with no offset.
+func NewClock(config ClockConfig) *Clock {
+	if config.WarnThreshold <= 0 {
+		config.WarnThreshold = defaultSkewWarning
+	}
+	if config.OnSkew == nil {
+		config.OnSkew = func(offset time.Duration) {
+			log.Printf("Warning: FalconX clock is off from the local clock by %v; signing with the server time", offset)
+		}
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Clock{config: config}
+}
+
+// Now returns the estimated server time.
+func (clock *Clock) Now() time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.config.Now().Add(clock.offset)
+}
+
+// Offset returns how far the server clock is ahead of the local clock, and when it was
+// last measured. A nil Clock has no offset and was never measured.
+func (clock *Clock) Offset() (time.Duration, time.Time) {
+	if clock == nil {
+		return 0, time.Time{}
+	}
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.offset, clock.synced
+}
+
+// Observe measures the offset from a response's Date header, given when its request was
+// sent and when the response arrived. It reports whether the offset changed; responses
+// without a valid Date header are ignored.
+func (clock *Clock) Observe(date string, sent, received time.Time) bool {
+	if clock == nil || date == "" {
+		return false
+	}
+	server, err := http.ParseTime(date)
+	if err != nil {
+		return false
+	}
+	// Date is truncated to the second, so the server time lies anywhere in the
+	// following second; take its middle and compare with the middle of the round trip.
+	server = server.Add(clockResolution / 2)
+	local := sent.Add(received.Sub(sent) / 2)
+	offset := server.Sub(local)
+
+	clock.mu.Lock()
+	change := offset - clock.offset
+	if !clock.synced.IsZero() && change < clockResolution && change > -clockResolution {
+		clock.synced = received
+		clock.mu.Unlock()
+		return false
+	}
+	clock.offset, clock.synced = offset, received
+	clock.mu.Unlock()
+
+	if offset > clock.config.WarnThreshold || offset < -clock.config.WarnThreshold {
+		clock.config.OnSkew(offset)
+	}
+	return true
+}
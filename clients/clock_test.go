@@ -0,0 +1,72 @@
+package clients_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+func TestClockObserve(t *testing.T) {
+	local := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	var skews []time.Duration
+	clock := clients.NewClock(clients.ClockConfig{
+		WarnThreshold: 5 * time.Second,
+		OnSkew:        func(offset time.Duration) { skews = append(skews, offset) },
+		Now:           func() time.Time { return local },
+	})
+	date := func(offset time.Duration) string {
+		return local.Add(offset).Format(http.TimeFormat)
+	}
+	// Each request takes a second; the server time is read at the middle of the Date
+	// header's second and compared with the middle of the round trip.
+	observe := func(header string, sent time.Time) bool {
+		return clock.Observe(header, sent, sent.Add(time.Second))
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		sent    time.Time
+		changed bool
+		offset  time.Duration
+	}{
+		{"first sample is applied however small", date(0), local, true, 0},
+		{"missing Date is ignored", "", local, false, 0},
+		{"invalid Date is ignored", "yesterday", local, false, 0},
+		{"change within the dead band is ignored", date(0), local.Add(-900 * time.Millisecond), false, 0},
+		{"change of a second is applied", date(time.Second), local, true, time.Second},
+		{"large skew is applied", date(10 * time.Second), local, true, 10 * time.Second},
+		{"noise around the skew is ignored", date(10 * time.Second), local.Add(500 * time.Millisecond), false, 10 * time.Second},
+		{"clock falling behind is applied", date(-3 * time.Second), local, true, -3 * time.Second},
+	}
+	for _, test := range tests {
+		if changed := observe(test.header, test.sent); changed != test.changed {
+			t.Errorf("%s: changed = %v, want %v", test.name, changed, test.changed)
+		}
+		if offset, _ := clock.Offset(); offset != test.offset {
+			t.Errorf("%s: offset = %v, want %v", test.name, offset, test.offset)
+		}
+	}
+
+	if len(skews) != 1 || skews[0] != 10*time.Second {
+		t.Errorf("OnSkew called with %v, want only the 10s skew", skews)
+	}
+	if now := clock.Now(); !now.Equal(local.Add(-3 * time.Second)) {
+		t.Errorf("Now = %v, want the local time corrected by -3s", now)
+	}
+}
+
+func TestNilClock(t *testing.T) {
+	var clock *clients.Clock
+	if clock.Observe(time.Now().Format(http.TimeFormat), time.Now(), time.Now()) {
+		t.Error("nil clock applied an offset")
+	}
+	if drift := time.Since(clock.Now()); drift < 0 || drift > time.Second {
+		t.Errorf("nil clock is %v off the local clock", drift)
+	}
+	if offset, synced := clock.Offset(); offset != 0 || !synced.IsZero() {
+		t.Errorf("nil clock offset = %v, %v; want 0 and never synced", offset, synced)
+	}
+}
@@ -0,0 +1,123 @@
+package clients
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrMissingCredentials is returned when credentials lack an API key, a passphrase or a
+// way to sign.
+var ErrMissingCredentials = errors.New("credentials need an api key, a passphrase and a secret or signer")
+
+// Credentials authenticate requests: the API key and passphrase sent as headers and the
+// secret, or a Signer holding it, used for FX-ACCESS-SIGN.
+type Credentials struct {
+	APIKey     string
+	Secret     string
+	Passphrase string
+	// Signer, when set, signs instead of Secret, which can then be left empty.
+	Signer Signer
+}
+
+// Validate checks that every credential is present and that Secret, when used, is valid
+// base64.
+func (credentials Credentials) Validate() error {
+	if credentials.APIKey == "" || credentials.Passphrase == "" ||
+		(credentials.Secret == "" && credentials.Signer == nil) {
+		return ErrMissingCredentials
+	}
+	if credentials.Signer == nil {
+		if _, err := NewSecretSigner(credentials.Secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sign signs message with Signer, or with Secret when no Signer is set.
+func (credentials Credentials) Sign(message string) (string, error) {
+	if credentials.Signer != nil {
+		return credentials.Signer.Sign(message)
+	}
+	return GenerateSig(message, credentials.Secret)
+}
+
+// CredentialsProvider supplies the credentials to sign the next request with. Set it on
+// RestClientConfig and SocketClientConfig to change credentials without rebuilding the
+// clients. Each request takes one snapshot, so it is signed with a consistent set.
+type CredentialsProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// RotationEvent describes a credential rotation. It carries no secrets.
+type RotationEvent struct {
+	PreviousAPIKey string
+	APIKey         string
+	Reason         string
+	Time           time.Time
+}
+
+// RotatingCredentials is a CredentialsProvider whose credentials can be replaced at
+// runtime. Requests already signed keep the old credentials; requests built after
+// Rotate returns use the new ones.
+type RotatingCredentials struct {
+	mu        sync.RWMutex
+	current   Credentials
+	rotatedAt time.Time
+	hooks     []func(RotationEvent)
+}
+
+// NewRotatingCredentials creates a provider starting with initial.
+func NewRotatingCredentials(initial Credentials) (*RotatingCredentials, error) {
+	if err := initial.Validate(); err != nil {
+		return nil, err
+	}
+	return &RotatingCredentials{current: initial}, nil
+}
+
+// Credentials returns the current credentials.
+func (rotating *RotatingCredentials) Credentials() (Credentials, error) {
+	rotating.mu.RLock()
+	defer rotating.mu.RUnlock()
+	return rotating.current, nil
+}
+
+// Rotate replaces the credentials with next after validating them, then runs the
+// OnRotate hooks. reason is recorded in the RotationEvent.
+func (rotating *RotatingCredentials) Rotate(next Credentials, reason string) error {
+	if err := next.Validate(); err != nil {
+		return err
+	}
+
+	rotating.mu.Lock()
+	event := RotationEvent{
+		PreviousAPIKey: rotating.current.APIKey,
+		APIKey:         next.APIKey,
+		Reason:         reason,
+		Time:           time.Now().UTC(),
+	}
+	rotating.current = next
+	rotating.rotatedAt = event.Time
+	hooks := append([]func(RotationEvent){}, rotating.hooks...)
+	rotating.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(event)
+	}
+	return nil
+}
+
+// RotatedAt returns when Rotate last succeeded, or the zero time if it never did.
+func (rotating *RotatingCredentials) RotatedAt() time.Time {
+	rotating.mu.RLock()
+	defer rotating.mu.RUnlock()
+	return rotating.rotatedAt
+}
+
+// OnRotate registers hook to run after every rotation, e.g. to write an audit record.
+func (rotating *RotatingCredentials) OnRotate(hook func(RotationEvent)) {
+	rotating.mu.Lock()
+	defer rotating.mu.Unlock()
+	rotating.hooks = append(rotating.hooks, hook)
+}
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -49,11 +50,305 @@ type FalconXWarning struct {
 	Side    string `json:"side"`
 }
 
+// NullFloat64 is a price FalconX may send as null, such as sell_price on a buy quote
+// or price_executed before execution. Prices arrive as quoted decimals like "12650.5"
+// or as plain JSON numbers; anything else is rejected.
+type NullFloat64 struct {
+	Float64 float64
+	// Valid is false when the value was null or absent.
+	Valid bool
+}
+
+// NewNullFloat64 returns a valid NullFloat64 holding value.
+func NewNullFloat64(value float64) NullFloat64 {
+	return NullFloat64{Float64: value, Valid: true}
+}
+
+// Get returns the value and whether it is set.
+func (n NullFloat64) Get() (float64, bool) {
+	return n.Float64, n.Valid
+}
+
+// Or returns the value, or fallback when it is null.
+func (n NullFloat64) Or(fallback float64) float64 {
+	if !n.Valid {
+		return fallback
+	}
+	return n.Float64
+}
+
+func (n NullFloat64) String() string {
+	if !n.Valid {
+		return "null"
+	}
+	return strconv.FormatFloat(n.Float64, 'f', -1, 64)
+}
+
+// MarshalJSON writes the value as a quoted decimal, the way FalconX sends it, or null.
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(strconv.FormatFloat(n.Float64, 'f', -1, 64))), nil
+}
+
+func (n *NullFloat64) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		*n = NullFloat64{}
+		return nil
+	}
+	if len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"' {
+		text = text[1 : len(text)-1]
+	}
+	value, ok := parseDecimal(text)
+	if !ok {
+		return fmt.Errorf("invalid price %s, must be a decimal or null", data)
+	}
+	*n = NewNullFloat64(value)
+	return nil
+}
+
+// parseDecimal parses a plain decimal such as -12.5 or 1e-8, rejecting the hex,
+// infinity and NaN forms strconv.ParseFloat also accepts.
+func parseDecimal(text string) (float64, bool) {
+	i := 0
+	if i < len(text) && text[i] == '-' {
+		i++
+	}
+	start := i
+	for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+		i++
+	}
+	integer := i - start
+	fraction := 0
+	if i < len(text) && text[i] == '.' {
+		i++
+		fractionStart := i
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			i++
+		}
+		fraction = i - fractionStart
+	}
+	if integer == 0 && fraction == 0 {
+		return 0, false
+	}
+	if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+		i++
+		if i < len(text) && (text[i] == '+' || text[i] == '-') {
+			i++
+		}
+		exponentStart := i
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			i++
+		}
+		if i == exponentStart {
+			return 0, false
+		}
+	}
+	if i != len(text) {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// NullTime is a timestamp FalconX may send as null, such as t_execute before
+// execution. Timestamps must be RFC 3339 with an optional fraction of a second and a Z
+// or numeric zone, e.g. 2019-06-27T11:59:21.875725+00:00. They are decoded in UTC.
+type NullTime struct {
+	Time time.Time
+	// Valid is false when the value was null or absent.
+	Valid bool
+}
+
+// NewNullTime returns a valid NullTime holding t.
+func NewNullTime(t time.Time) NullTime {
+	return NullTime{Time: t, Valid: true}
+}
+
+// Get returns the time and whether it is set.
+func (n NullTime) Get() (time.Time, bool) {
+	return n.Time, n.Valid
+}
+
+// Or returns the time, or fallback when it is null.
+func (n NullTime) Or(fallback time.Time) time.Time {
+	if !n.Valid {
+		return fallback
+	}
+	return n.Time
+}
+
+func (n NullTime) String() string {
+	if !n.Valid {
+		return "null"
+	}
+	return n.Time.Format(time.RFC3339Nano)
+}
+
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return n.Time.MarshalJSON()
+}
+
+func (n *NullTime) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		*n = NullTime{}
+		return nil
+	}
+	if len(text) < 2 || text[0] != '"' || text[len(text)-1] != '"' {
+		return fmt.Errorf("invalid timestamp %s, must be a string or null", data)
+	}
+	t, ok := parseTimestamp(text[1 : len(text)-1])
+	if !ok {
+		return fmt.Errorf("invalid timestamp %s", data)
+	}
+	*n = NewNullTime(t)
+	return nil
+}
+
+// NullSide is an executed side FalconX may send as null, such as side_executed before
+// execution. Set values are buy or sell.
+type NullSide struct {
+	Side string
+	// Valid is false when the value was null or absent.
+	Valid bool
+}
+
+// NewNullSide returns a valid NullSide holding side.
+func NewNullSide(side string) NullSide {
+	return NullSide{Side: side, Valid: true}
+}
+
+// Get returns the side and whether it is set.
+func (n NullSide) Get() (string, bool) {
+	return n.Side, n.Valid
+}
+
+// Or returns the side, or fallback when it is null.
+func (n NullSide) Or(fallback string) string {
+	if !n.Valid {
+		return fallback
+	}
+	return n.Side
+}
+
+func (n NullSide) String() string {
+	if !n.Valid {
+		return "null"
+	}
+	return n.Side
+}
+
+func (n NullSide) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(n.Side)), nil
+}
+
+func (n *NullSide) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "null":
+		*n = NullSide{}
+	case `"buy"`:
+		*n = NewNullSide("buy")
+	case `"sell"`:
+		*n = NewNullSide("sell")
+	default:
+		return fmt.Errorf("invalid side %s, must be buy, sell or null", data)
+	}
+	return nil
+}
+
+// parseTimestamp parses the RFC 3339 timestamps FalconX emits, such as
+// 2019-06-27T11:59:21.875725+00:00, without the allocations of time.Parse. The result
+// is in UTC. Other layouts report false.
+func parseTimestamp(text string) (time.Time, bool) {
+	// 2006-01-02T15:04:05 is followed by an optional fraction and a zone.
+	if len(text) < 20 || text[4] != '-' || text[7] != '-' || (text[10] != 'T' && text[10] != 't') ||
+		text[13] != ':' || text[16] != ':' {
+		return time.Time{}, false
+	}
+	year, ok1 := digits(text[0:4])
+	month, ok2 := digits(text[5:7])
+	day, ok3 := digits(text[8:10])
+	hour, ok4 := digits(text[11:13])
+	minute, ok5 := digits(text[14:16])
+	second, ok6 := digits(text[17:19])
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6) || month < 1 || month > 12 || day < 1 || day > 31 ||
+		hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, false
+	}
+
+	rest := text[19:]
+	nanos := 0
+	if rest[0] == '.' {
+		n := 1
+		for n < len(rest) && rest[n] >= '0' && rest[n] <= '9' {
+			n++
+		}
+		if n == 1 || n > 10 {
+			return time.Time{}, false
+		}
+		fraction, _ := digits(rest[1:n])
+		for i := n; i <= 9; i++ {
+			fraction *= 10
+		}
+		nanos = fraction
+		rest = rest[n:]
+	}
+
+	offset := 0
+	switch {
+	case rest == "Z" || rest == "z":
+	case len(rest) == 6 && (rest[0] == '+' || rest[0] == '-') && rest[3] == ':':
+		zoneHour, okHour := digits(rest[1:3])
+		zoneMinute, okMinute := digits(rest[4:6])
+		if !okHour || !okMinute || zoneHour > 23 || zoneMinute > 59 {
+			return time.Time{}, false
+		}
+		offset = (zoneHour*60 + zoneMinute) * 60
+		if rest[0] == '-' {
+			offset = -offset
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, nanos, time.UTC)
+	if t.Day() != day {
+		// time.Date normalized an invalid date such as February 30.
+		return time.Time{}, false
+	}
+	return t.Add(-time.Duration(offset) * time.Second), true
+}
+
+// digits parses a run of ASCII digits.
+func digits(text string) (int, bool) {
+	value := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		value = value*10 + int(c-'0')
+	}
+	return value, true
+}
+
 type QuoteResponse struct {
 	Status        string           `json:"status"`
 	FxQuoteId     string           `json:"fx_quote_id"`
-	BuyPrice      float64          `json:"buy_price,string"`
-	SellPrice     float64          `json:"sell_price,string"`
+	BuyPrice      NullFloat64      `json:"buy_price"`
+	SellPrice     NullFloat64      `json:"sell_price"`
 	Platform      string           `json:"platform"`
 	TokenPair     TokenPair        `json:"token_pair"`
 	Quantity      Quantity         `json:"quantity_requested"`
@@ -62,26 +357,35 @@ type QuoteResponse struct {
 	SideRequested string           `json:"side_requested"`
 	QuoteTime     time.Time        `json:"t_quote"`
 	ExpiryTime    time.Time        `json:"t_expiry"`
-	ExecutionTime time.Time        `json:"t_execute"`
+	ExecutionTime NullTime         `json:"t_execute"`
 	IsFilled      bool             `json:"is_filled"`
+	SideExecuted  NullSide         `json:"side_executed"`
+	PriceExecuted NullFloat64      `json:"price_executed"`
+	GrossFeeBps   float64          `json:"gross_fee_bps,string"`
+	GrossFeeUSD   float64          `json:"gross_fee_usd,string"`
+	RebateBps     float64          `json:"rebate_bps,string"`
+	RebateUSD     float64          `json:"rebate_usd,string"`
+	FeeBps        float64          `json:"fee_bps,string"`
+	FeeUSD        float64          `json:"fee_usd,string"`
 	TraderEmail   string           `json:"trader_email"`
 	Error         FalconXError     `json:"error"`
 	Warnings      []FalconXWarning `json:"warnings"`
 	ClientOrderId string           `json:"client_order_id"`
+	Simulated     bool             `json:"simulated,omitempty"`
 }
 
 type OrderResponse struct {
 	Status        string           `json:"status"`
 	FxQuoteId     string           `json:"fx_quote_id"`
-	BuyPrice      float64          `json:"buy_price,string"`
-	SellPrice     float64          `json:"sell_price,string"`
+	BuyPrice      NullFloat64      `json:"buy_price"`
+	SellPrice     NullFloat64      `json:"sell_price"`
 	Platform      string           `json:"platform"`
 	TokenPair     TokenPair        `json:"token_pair"`
 	Quantity      Quantity         `json:"quantity_requested"`
 	SideRequested string           `json:"side_requested"`
 	QuoteTime     time.Time        `json:"t_quote"`
 	ExpiryTime    time.Time        `json:"t_expiry"`
-	ExecutionTime time.Time        `json:"t_execute"`
+	ExecutionTime NullTime         `json:"t_execute"`
 	IsFilled      bool             `json:"is_filled"`
 	GrossFeeBps   float64          `json:"gross_fee_bps,string"`
 	GrossFeeUSD   float64          `json:"gross_fee_usd,string"`
@@ -89,15 +393,16 @@ type OrderResponse struct {
 	RebateUSD     float64          `json:"rebate_usd,string"`
 	FeeBps        float64          `json:"fee_bps,string"`
 	FeeUSD        float64          `json:"fee_usd,string"`
-	SideExecuted  string           `json:"side_executed"`
+	SideExecuted  NullSide         `json:"side_executed"`
 	TraderEmail   string           `json:"trader_email"`
 	OrderType     string           `json:"order_type"`
 	TimeInForce   string           `json:"time_in_force"`
-	LimitPrice    float64          `json:"limit_price,string"`
+	LimitPrice    NullFloat64      `json:"limit_price"`
 	SlippageBps   float64          `json:"slippage_bps,string"`
 	Error         FalconXError     `json:"error"`
 	Warnings      []FalconXWarning `json:"warnings"`
 	ClientOrderId string           `json:"client_order_id"`
+	Simulated     bool             `json:"simulated,omitempty"`
 }
 
 type Balance struct {
@@ -152,6 +457,19 @@ type SubscriptionRequest struct {
 	ClientRequestID string    `json:"client_request_id"`
 }
 
+type PriceLevel struct {
+	Quantity  float64 `json:"quantity"`
+	BuyPrice  float64 `json:"buy_price"`
+	SellPrice float64 `json:"sell_price"`
+}
+
+type PriceLadder struct {
+	ClientRequestID string       `json:"client_request_id"`
+	TokenPair       TokenPair    `json:"token_pair"`
+	Levels          []PriceLevel `json:"levels"`
+	QuoteTime       time.Time    `json:"t_quote"`
+}
+
 type UserConfigRequest struct {
 	MessageType     string `json:"message_type"`
 	ClientRequestID string `json:"client_request_id"`
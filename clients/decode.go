@@ -0,0 +1,687 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxPooledBuffer keeps unusually large responses from pinning memory in the pool.
+const maxPooledBuffer = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// errFastDecode makes decodeResponse fall back to encoding/json.
+var errFastDecode = errors.New("unexpected JSON for fast decoder")
+
+// decodeResponse reads body into a pooled buffer and decodes it into result. As the
+// client always has, it uses a json.Decoder, so anything after the first JSON value is
+// ignored. QuoteResponse and OrderResponse, decoded on every quote and order, take a
+// hand-written path that allocates a single string for all their text fields. It
+// accepts only input it decodes exactly as encoding/json would; anything else,
+// including trailing data and every input encoding/json rejects, falls back to the
+// json.Decoder, as do all other result types.
+func decodeResponse(body io.Reader, result interface{}) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+	if _, err := buf.ReadFrom(body); err != nil {
+		return err
+	}
+
+	switch result := result.(type) {
+	case *QuoteResponse:
+		*result = QuoteResponse{}
+		if decodeQuoteResponse(buf.String(), result) == nil {
+			return nil
+		}
+		*result = QuoteResponse{}
+	case *OrderResponse:
+		*result = OrderResponse{}
+		if decodeOrderResponse(buf.String(), result) == nil {
+			return nil
+		}
+		*result = OrderResponse{}
+	}
+	return json.NewDecoder(buf).Decode(result)
+}
+
+func decodeQuoteResponse(data string, quote *QuoteResponse) error {
+	reader := jsonReader{data: data}
+	err := reader.object(func(key string) error {
+		switch key {
+		case "status":
+			return reader.stringField(&quote.Status)
+		case "fx_quote_id":
+			return reader.stringField(&quote.FxQuoteId)
+		case "buy_price":
+			return reader.nullFloatField(&quote.BuyPrice)
+		case "sell_price":
+			return reader.nullFloatField(&quote.SellPrice)
+		case "platform":
+			return reader.stringField(&quote.Platform)
+		case "token_pair":
+			return reader.tokenPair(&quote.TokenPair)
+		case "quantity_requested":
+			return reader.quantity(&quote.Quantity)
+		case "position_in":
+			return reader.quantity(&quote.PositionIn)
+		case "position_out":
+			return reader.quantity(&quote.PositionOut)
+		case "side_requested":
+			return reader.stringField(&quote.SideRequested)
+		case "t_quote":
+			return reader.timeField(&quote.QuoteTime)
+		case "t_expiry":
+			return reader.timeField(&quote.ExpiryTime)
+		case "t_execute":
+			return reader.nullTimeField(&quote.ExecutionTime)
+		case "is_filled":
+			return reader.boolField(&quote.IsFilled)
+		case "side_executed":
+			return reader.sideField(&quote.SideExecuted)
+		case "price_executed":
+			return reader.nullFloatField(&quote.PriceExecuted)
+		case "gross_fee_bps":
+			return reader.floatField(&quote.GrossFeeBps)
+		case "gross_fee_usd":
+			return reader.floatField(&quote.GrossFeeUSD)
+		case "rebate_bps":
+			return reader.floatField(&quote.RebateBps)
+		case "rebate_usd":
+			return reader.floatField(&quote.RebateUSD)
+		case "fee_bps":
+			return reader.floatField(&quote.FeeBps)
+		case "fee_usd":
+			return reader.floatField(&quote.FeeUSD)
+		case "trader_email":
+			return reader.stringField(&quote.TraderEmail)
+		case "error":
+			return reader.falconXError(&quote.Error)
+		case "warnings":
+			return reader.warnings(&quote.Warnings)
+		case "client_order_id":
+			return reader.stringField(&quote.ClientOrderId)
+		case "simulated":
+			return reader.boolField(&quote.Simulated)
+		}
+		return reader.unknown(key)
+	})
+	if err != nil {
+		return err
+	}
+	return reader.end()
+}
+
+func decodeOrderResponse(data string, order *OrderResponse) error {
+	reader := jsonReader{data: data}
+	err := reader.object(func(key string) error {
+		switch key {
+		case "status":
+			return reader.stringField(&order.Status)
+		case "fx_quote_id":
+			return reader.stringField(&order.FxQuoteId)
+		case "buy_price":
+			return reader.nullFloatField(&order.BuyPrice)
+		case "sell_price":
+			return reader.nullFloatField(&order.SellPrice)
+		case "platform":
+			return reader.stringField(&order.Platform)
+		case "token_pair":
+			return reader.tokenPair(&order.TokenPair)
+		case "quantity_requested":
+			return reader.quantity(&order.Quantity)
+		case "side_requested":
+			return reader.stringField(&order.SideRequested)
+		case "t_quote":
+			return reader.timeField(&order.QuoteTime)
+		case "t_expiry":
+			return reader.timeField(&order.ExpiryTime)
+		case "t_execute":
+			return reader.nullTimeField(&order.ExecutionTime)
+		case "is_filled":
+			return reader.boolField(&order.IsFilled)
+		case "gross_fee_bps":
+			return reader.floatField(&order.GrossFeeBps)
+		case "gross_fee_usd":
+			return reader.floatField(&order.GrossFeeUSD)
+		case "rebate_bps":
+			return reader.floatField(&order.RebateBps)
+		case "rebate_usd":
+			return reader.floatField(&order.RebateUSD)
+		case "fee_bps":
+			return reader.floatField(&order.FeeBps)
+		case "fee_usd":
+			return reader.floatField(&order.FeeUSD)
+		case "side_executed":
+			return reader.sideField(&order.SideExecuted)
+		case "trader_email":
+			return reader.stringField(&order.TraderEmail)
+		case "order_type":
+			return reader.stringField(&order.OrderType)
+		case "time_in_force":
+			return reader.stringField(&order.TimeInForce)
+		case "limit_price":
+			return reader.nullFloatField(&order.LimitPrice)
+		case "slippage_bps":
+			return reader.floatField(&order.SlippageBps)
+		case "error":
+			return reader.falconXError(&order.Error)
+		case "warnings":
+			return reader.warnings(&order.Warnings)
+		case "client_order_id":
+			return reader.stringField(&order.ClientOrderId)
+		case "simulated":
+			return reader.boolField(&order.Simulated)
+		}
+		return reader.unknown(key)
+	})
+	if err != nil {
+		return err
+	}
+	return reader.end()
+}
+
+// jsonReader scans a JSON document held in a string. Strings without escapes are
+// returned as substrings of data, so they share its memory instead of allocating.
+type jsonReader struct {
+	data string
+	pos  int
+}
+
+func (reader *jsonReader) skipSpace() {
+	for reader.pos < len(reader.data) {
+		switch reader.data[reader.pos] {
+		case ' ', '\t', '\n', '\r':
+			reader.pos++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end of data.
+func (reader *jsonReader) peek() byte {
+	reader.skipSpace()
+	if reader.pos >= len(reader.data) {
+		return 0
+	}
+	return reader.data[reader.pos]
+}
+
+func (reader *jsonReader) consume(c byte) error {
+	if reader.peek() != c {
+		return errFastDecode
+	}
+	reader.pos++
+	return nil
+}
+
+func (reader *jsonReader) literal(word string) bool {
+	reader.skipSpace()
+	if len(reader.data)-reader.pos >= len(word) && reader.data[reader.pos:reader.pos+len(word)] == word {
+		reader.pos += len(word)
+		return true
+	}
+	return false
+}
+
+func (reader *jsonReader) end() error {
+	if reader.peek() != 0 {
+		return errFastDecode
+	}
+	return nil
+}
+
+// object calls field for every key of an object, which must consume the value. A null
+// object is skipped.
+func (reader *jsonReader) object(field func(key string) error) error {
+	if reader.literal("null") {
+		return nil
+	}
+	if err := reader.consume('{'); err != nil {
+		return err
+	}
+	if reader.peek() == '}' {
+		reader.pos++
+		return nil
+	}
+	for {
+		key, err := reader.str()
+		if err != nil {
+			return err
+		}
+		if err := reader.consume(':'); err != nil {
+			return err
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+		switch reader.peek() {
+		case ',':
+			reader.pos++
+		case '}':
+			reader.pos++
+			return nil
+		default:
+			return errFastDecode
+		}
+	}
+}
+
+// str reads a string. Strings with escape sequences or invalid UTF-8 are decoded by
+// encoding/json, which unescapes them and replaces invalid bytes.
+func (reader *jsonReader) str() (string, error) {
+	start := reader.pos
+	value, plain, err := reader.rawStr()
+	if err != nil || plain {
+		return value, err
+	}
+	var unescaped string
+	if err := json.Unmarshal([]byte(reader.data[start:reader.pos]), &unescaped); err != nil {
+		return "", errFastDecode
+	}
+	return unescaped, nil
+}
+
+// plainStr reads a string that needs no decoding. Values read through it are parsed
+// from their raw bytes by time.Time and the Null types, so escaped ones are left to
+// encoding/json.
+func (reader *jsonReader) plainStr() (string, error) {
+	value, plain, err := reader.rawStr()
+	if err != nil {
+		return "", err
+	}
+	if !plain {
+		return "", errFastDecode
+	}
+	return value, nil
+}
+
+// rawStr reads a string without decoding it. plain reports whether it is valid UTF-8
+// without escape sequences, so the raw value is also the decoded one.
+func (reader *jsonReader) rawStr() (value string, plain bool, err error) {
+	if err := reader.consume('"'); err != nil {
+		return "", false, err
+	}
+	start := reader.pos
+	escaped, ascii := false, true
+	for reader.pos < len(reader.data) {
+		switch c := reader.data[reader.pos]; {
+		case c == '\\':
+			escaped = true
+			reader.pos += 2
+			continue
+		case c == '"':
+			value := reader.data[start:reader.pos]
+			reader.pos++
+			return value, !escaped && (ascii || utf8.ValidString(value)), nil
+		case c < 0x20:
+			return "", false, errFastDecode
+		case c >= utf8.RuneSelf:
+			ascii = false
+		}
+		reader.pos++
+	}
+	return "", false, errFastDecode
+}
+
+// unknown skips the value of a key the decoder does not handle. encoding/json matches
+// keys case-insensitively, so a key that may fold onto a known one is left to it.
+func (reader *jsonReader) unknown(key string) error {
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c >= 'A' && c <= 'Z' || c >= utf8.RuneSelf {
+			return errFastDecode
+		}
+	}
+	return reader.skip()
+}
+
+// number reads a bare JSON number.
+func (reader *jsonReader) number() (string, error) {
+	reader.skipSpace()
+	start := reader.pos
+	for reader.pos < len(reader.data) && isNumberByte(reader.data[reader.pos]) {
+		reader.pos++
+	}
+	text := reader.data[start:reader.pos]
+	if !isJSONNumber(text) {
+		return "", errFastDecode
+	}
+	return text, nil
+}
+
+// stringField reads a string into dst, leaving it unchanged for null.
+func (reader *jsonReader) stringField(dst *string) error {
+	if reader.literal("null") {
+		return nil
+	}
+	value, err := reader.str()
+	if err != nil {
+		return err
+	}
+	*dst = value
+	return nil
+}
+
+// floatField reads a ",string" number into dst, leaving it unchanged for null. Like
+// encoding/json it requires the quotes.
+func (reader *jsonReader) floatField(dst *float64) error {
+	if reader.literal("null") {
+		return nil
+	}
+	text, err := reader.plainStr()
+	if err != nil {
+		return err
+	}
+	if !isJSONNumber(text) {
+		return errFastDecode
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return errFastDecode
+	}
+	*dst = value
+	return nil
+}
+
+// nullFloatField reads a number, quoted or not, or null into dst, accepting what
+// NullFloat64.UnmarshalJSON accepts.
+func (reader *jsonReader) nullFloatField(dst *NullFloat64) error {
+	if reader.literal("null") {
+		*dst = NullFloat64{}
+		return nil
+	}
+	var text string
+	var err error
+	if reader.peek() == '"' {
+		text, err = reader.plainStr()
+	} else {
+		text, err = reader.number()
+	}
+	if err != nil {
+		return err
+	}
+	value, ok := parseDecimal(text)
+	if !ok {
+		return errFastDecode
+	}
+	*dst = NewNullFloat64(value)
+	return nil
+}
+
+func isNumberByte(c byte) bool {
+	return c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
+
+// isJSONNumber reports whether text follows the JSON number grammar.
+func isJSONNumber(text string) bool {
+	i := 0
+	if i < len(text) && text[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(text) && text[i] == '0':
+		i++
+	case i < len(text) && text[i] >= '1' && text[i] <= '9':
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			i++
+		}
+	default:
+		return false
+	}
+	if i < len(text) && text[i] == '.' {
+		i++
+		start := i
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+		i++
+		if i < len(text) && (text[i] == '+' || text[i] == '-') {
+			i++
+		}
+		start := i
+		for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return false
+		}
+	}
+	return i == len(text)
+}
+
+func (reader *jsonReader) boolField(dst *bool) error {
+	switch {
+	case reader.literal("true"):
+		*dst = true
+	case reader.literal("false"):
+		*dst = false
+	case reader.literal("null"):
+	default:
+		return errFastDecode
+	}
+	return nil
+}
+
+// timeField reads a timestamp into dst, leaving it unchanged for null. The zone is
+// kept as time.Time.UnmarshalJSON keeps it: UTC for Z, the local zone when its offset
+// matches, and an unnamed fixed zone otherwise.
+func (reader *jsonReader) timeField(dst *time.Time) error {
+	if reader.literal("null") {
+		return nil
+	}
+	text, err := reader.plainStr()
+	if err != nil {
+		return err
+	}
+	// parseTimestamp also accepts lower-case t and z, which time.Time does not.
+	if len(text) < 20 || text[10] != 'T' || text[len(text)-1] == 'z' || strings.HasSuffix(text, "-00:00") {
+		return errFastDecode
+	}
+	value, ok := parseTimestamp(text)
+	if !ok {
+		return errFastDecode
+	}
+	if text[len(text)-1] != 'Z' {
+		offset := zoneOffset(text[len(text)-6:])
+		if _, local := value.In(time.Local).Zone(); local == offset {
+			value = value.In(time.Local)
+		} else {
+			value = value.In(time.FixedZone("", offset))
+		}
+	}
+	*dst = value
+	return nil
+}
+
+// zoneOffset converts a validated +hh:mm zone to seconds east of UTC.
+func zoneOffset(zone string) int {
+	hours, _ := digits(zone[1:3])
+	minutes, _ := digits(zone[4:6])
+	offset := (hours*60 + minutes) * 60
+	if zone[0] == '-' {
+		offset = -offset
+	}
+	return offset
+}
+
+// nullTimeField reads a timestamp or null into dst, accepting what
+// NullTime.UnmarshalJSON accepts.
+func (reader *jsonReader) nullTimeField(dst *NullTime) error {
+	if reader.literal("null") {
+		*dst = NullTime{}
+		return nil
+	}
+	text, err := reader.plainStr()
+	if err != nil {
+		return err
+	}
+	value, ok := parseTimestamp(text)
+	if !ok {
+		return errFastDecode
+	}
+	*dst = NewNullTime(value)
+	return nil
+}
+
+// sideField reads buy, sell or null into dst.
+func (reader *jsonReader) sideField(dst *NullSide) error {
+	if reader.literal("null") {
+		*dst = NullSide{}
+		return nil
+	}
+	side, err := reader.plainStr()
+	if err != nil {
+		return err
+	}
+	if side != "buy" && side != "sell" {
+		return errFastDecode
+	}
+	*dst = NewNullSide(side)
+	return nil
+}
+
+func (reader *jsonReader) tokenPair(dst *TokenPair) error {
+	return reader.object(func(key string) error {
+		switch key {
+		case "base_token":
+			return reader.stringField(&dst.BaseToken)
+		case "quote_token":
+			return reader.stringField(&dst.QuoteToken)
+		}
+		return reader.unknown(key)
+	})
+}
+
+func (reader *jsonReader) quantity(dst *Quantity) error {
+	return reader.object(func(key string) error {
+		switch key {
+		case "token":
+			return reader.stringField(&dst.Token)
+		case "value":
+			return reader.floatField(&dst.Value)
+		}
+		return reader.unknown(key)
+	})
+}
+
+func (reader *jsonReader) falconXError(dst *FalconXError) error {
+	return reader.object(func(key string) error {
+		switch key {
+		case "code":
+			return reader.stringField(&dst.Code)
+		case "reason":
+			return reader.stringField(&dst.Reason)
+		}
+		return reader.unknown(key)
+	})
+}
+
+func (reader *jsonReader) warnings(dst *[]FalconXWarning) error {
+	if reader.literal("null") {
+		*dst = nil
+		return nil
+	}
+	if err := reader.consume('['); err != nil {
+		return err
+	}
+	warnings := (*dst)[:0]
+	if reader.peek() == ']' {
+		reader.pos++
+		*dst = warnings
+		return nil
+	}
+	for {
+		var warning FalconXWarning
+		err := reader.object(func(key string) error {
+			switch key {
+			case "code":
+				return reader.stringField(&warning.Code)
+			case "message":
+				return reader.stringField(&warning.Message)
+			case "side":
+				return reader.stringField(&warning.Side)
+			}
+			return reader.unknown(key)
+		})
+		if err != nil {
+			return err
+		}
+		warnings = append(warnings, warning)
+		switch reader.peek() {
+		case ',':
+			reader.pos++
+		case ']':
+			reader.pos++
+			*dst = warnings
+			return nil
+		default:
+			return errFastDecode
+		}
+	}
+}
+
+// skip consumes any value.
+func (reader *jsonReader) skip() error {
+	switch reader.peek() {
+	case '"':
+		_, err := reader.str()
+		return err
+	case '{':
+		return reader.object(func(string) error { return reader.skip() })
+	case '[':
+		reader.pos++
+		if reader.peek() == ']' {
+			reader.pos++
+			return nil
+		}
+		for {
+			if err := reader.skip(); err != nil {
+				return err
+			}
+			switch reader.peek() {
+			case ',':
+				reader.pos++
+			case ']':
+				reader.pos++
+				return nil
+			default:
+				return errFastDecode
+			}
+		}
+	case 't':
+		if reader.literal("true") {
+			return nil
+		}
+	case 'f':
+		if reader.literal("false") {
+			return nil
+		}
+	case 'n':
+		if reader.literal("null") {
+			return nil
+		}
+	default:
+		_, err := reader.number()
+		return err
+	}
+	return errFastDecode
+}
@@ -0,0 +1,253 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const benchmarkQuoteJSON = `{
+  "status": "success",
+  "fx_quote_id": "00c884b056f949338788dfb59e495377",
+  "buy_price": "12650.5",
+  "sell_price": null,
+  "platform": "api",
+  "token_pair": {"base_token": "BTC", "quote_token": "USD"},
+  "quantity_requested": {"token": "BTC", "value": "10"},
+  "position_in": {"token": "USD", "value": "126505"},
+  "position_out": {"token": "BTC", "value": "10"},
+  "side_requested": "buy",
+  "t_quote": "2019-06-27T11:59:21.875725+00:00",
+  "t_expiry": "2019-06-27T11:59:22.875725+00:00",
+  "t_execute": "2019-06-27T11:59:22.102931+00:00",
+  "is_filled": true,
+  "side_executed": "buy",
+  "price_executed": "12650.5",
+  "gross_fee_bps": "2", "gross_fee_usd": "25.30", "rebate_bps": "0", "rebate_usd": "0",
+  "fee_bps": "2", "fee_usd": "25.30",
+  "trader_email": "trader@company.com",
+  "error": null,
+  "warnings": [{"code": "LOW_BALANCE", "message": "Balance below 10% of limit", "side": "buy"}],
+  "client_order_id": "d6f3e1fa-e148-4009-9c07-a87f9ae78d1a"
+}`
+
+const benchmarkOrderJSON = `{
+  "status": "success",
+  "fx_quote_id": "00c884b056f949338788dfb59e495377",
+  "buy_price": "8545.12",
+  "sell_price": null,
+  "platform": "api",
+  "token_pair": {"base_token": "BTC", "quote_token": "USD"},
+  "quantity_requested": {"token": "BTC", "value": "10"},
+  "side_requested": "buy",
+  "t_quote": "2019-06-27T11:59:21.875725+00:00",
+  "t_expiry": "2019-06-27T11:59:21.875725+00:00",
+  "t_execute": "2019-06-28T11:59:21.875725+00:00",
+  "is_filled": true,
+  "gross_fee_bps": "8", "gross_fee_usd": "68.36", "rebate_bps": "0", "rebate_usd": "0",
+  "fee_bps": "8", "fee_usd": "68.36",
+  "side_executed": "buy",
+  "trader_email": "trader1@company.com",
+  "order_type": "limit",
+  "time_in_force": "fok",
+  "limit_price": "8547.11",
+  "slippage_bps": "2",
+  "error": null,
+  "client_order_id": "d6f3e1fa-e148-4009-9c07-a87f9ae78d1a"
+}`
+
+// checkFastDecode fails if the fast path disagrees with a json.Decoder.
+func checkFastDecode(tb testing.TB, data string, fast, slow interface{}) {
+	tb.Helper()
+	fastErr := decodeResponse(strings.NewReader(data), fast)
+	slowErr := json.NewDecoder(strings.NewReader(data)).Decode(slow)
+	if (fastErr == nil) != (slowErr == nil) {
+		tb.Fatalf("decodeResponse error = %v, encoding/json error = %v", fastErr, slowErr)
+	}
+	if slowErr == nil && !reflect.DeepEqual(fast, slow) {
+		tb.Fatalf("decodeResponse = %+v, encoding/json = %+v", fast, slow)
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		// fast is whether the hand-written path decodes data itself.
+		fast bool
+	}{
+		{"quote sample", benchmarkQuoteJSON, true},
+		{"order sample", benchmarkOrderJSON, true},
+		{"empty", `{}`, true},
+		{"null document", `null`, true},
+		{"nulls", `{"status": null, "buy_price": null, "sell_price": null, "t_quote": null,
+			"t_execute": null, "side_executed": null, "fee_bps": null, "token_pair": null,
+			"error": null, "warnings": null, "is_filled": null}`, true},
+		{"escaped string", `{"trader_email": "trader\u0040company.com", "status": "a\"b\\c"}`, true},
+		{"non-ASCII string", `{"trader_email": "trädér@company.com"}`, true},
+		{"invalid UTF-8", "{\"trader_email\": \"tr\xffder\"}", true},
+		{"escaped side", `{"side_executed": "\u0062uy"}`, false},
+		{"escaped price", `{"buy_price": "1\u0032"}`, false},
+		{"escaped fee", `{"fee_bps": "\u0032"}`, false},
+		{"quoted price", `{"buy_price": "12650.5"}`, true},
+		{"bare price", `{"buy_price": 12650.5, "sell_price": -1e-8}`, true},
+		{"leading zero bare price", `{"buy_price": 012}`, false},
+		{"leading zero quoted price", `{"buy_price": "012"}`, true},
+		{"hex price", `{"buy_price": "0x10"}`, false},
+		{"quoted fee", `{"fee_bps": "2.5", "quantity_requested": {"token": "BTC", "value": "1e2"}}`, true},
+		{"bare fee", `{"fee_bps": 2.5}`, false},
+		{"bare quantity", `{"quantity_requested": {"token": "BTC", "value": 10}}`, false},
+		{"leading zero fee", `{"fee_bps": "02"}`, false},
+		{"fee with spaces", `{"fee_bps": " 2"}`, false},
+		{"quoted null fee", `{"fee_bps": "null"}`, false},
+		{"UTC time", `{"t_quote": "2019-06-27T11:59:21.875725Z"}`, true},
+		{"zero offset", `{"t_quote": "2019-06-27T11:59:21.875725+00:00"}`, true},
+		{"positive offset", `{"t_quote": "2019-06-27T11:59:21+02:00", "t_execute": "2019-06-27T11:59:22.5+02:00"}`, true},
+		{"negative offset", `{"t_expiry": "2019-06-27T11:59:21.1-05:30"}`, true},
+		{"negative zero offset", `{"t_quote": "2019-06-27T11:59:21-00:00"}`, false},
+		{"lower-case time", `{"t_quote": "2019-06-27t11:59:21z"}`, false},
+		{"space-separated time", `{"t_quote": "2019-06-27 11:59:21Z"}`, false},
+		{"invalid execute time", `{"t_execute": "2019-02-30T11:59:21Z"}`, false},
+		{"invalid side", `{"side_executed": "BUY"}`, false},
+		{"quoted bool", `{"is_filled": "true"}`, false},
+		{"upper-case key", `{"FEE_BPS": "3"}`, false},
+		{"mixed-case duplicate", `{"fee_bps": "2", "Fee_Bps": "3"}`, false},
+		{"nested upper-case key", `{"token_pair": {"BASE_TOKEN": "BTC"}}`, false},
+		// U+212A folds to k, so encoding/json matches it against known keys.
+		{"Kelvin sign key", "{\"fee_\u212a\": \"1\", \"\u212aey\": 1}", false},
+		{"unknown fields", `{"extra": [1, {"a": [true, false, null]}, "x"], "more": -0.5e+3}`, true},
+		{"invalid unknown number", `{"extra": +1}`, false},
+		{"invalid unknown literal", `{"extra": nul}`, false},
+		{"token pair string", `{"token_pair": "BTC/USD"}`, false},
+		{"trailing comma", `{"status": "success",}`, false},
+		{"trailing data", `{"status": "success"} {}`, false},
+		{"trailing garbage", `{"status": "success"}` + "\n<html>", false},
+		{"duplicate key", `{"status": "a", "status": "b"}`, true},
+		{"warnings", `{"warnings": [{"code": "A", "message": "m", "side": "buy"}, {"code": "B"}]}`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkFastDecode(t, test.data, &QuoteResponse{}, &QuoteResponse{})
+			checkFastDecode(t, test.data, &OrderResponse{}, &OrderResponse{})
+
+			fastQuote := decodeQuoteResponse(test.data, &QuoteResponse{}) == nil
+			fastOrder := decodeOrderResponse(test.data, &OrderResponse{}) == nil
+			if fastQuote != test.fast || fastOrder != test.fast {
+				t.Errorf("fast path used for quote %v, order %v, want %v", fastQuote, fastOrder, test.fast)
+			}
+		})
+	}
+}
+
+func TestDecodeResponseIgnoresTrailingData(t *testing.T) {
+	// Responses were always read with a json.Decoder, which stops after the first value.
+	tests := []struct {
+		name   string
+		result interface{}
+		want   interface{}
+	}{
+		{"quote", &QuoteResponse{}, &QuoteResponse{Status: "success"}},
+		{"order", &OrderResponse{}, &OrderResponse{Status: "success"}},
+		{"other type", &map[string]string{}, &map[string]string{"status": "success"}},
+	}
+	for _, test := range tests {
+		data := `{"status": "success"}` + "\n<html>trailing</html>"
+		if err := decodeResponse(strings.NewReader(data), test.result); err != nil {
+			t.Errorf("%s: decodeResponse error = %v, want nil", test.name, err)
+		} else if !reflect.DeepEqual(test.result, test.want) {
+			t.Errorf("%s: decodeResponse = %+v, want %+v", test.name, test.result, test.want)
+		}
+	}
+}
+
+func TestDecodeResponseResetsResult(t *testing.T) {
+	quote := QuoteResponse{Status: "stale", FeeBps: 9, Warnings: []FalconXWarning{{Code: "old", Side: "sell"}}}
+	if err := decodeResponse(strings.NewReader(`{"warnings": [{"code": "new"}]}`), &quote); err != nil {
+		t.Fatal(err)
+	}
+	want := QuoteResponse{Warnings: []FalconXWarning{{Code: "new"}}}
+	if !reflect.DeepEqual(quote, want) {
+		t.Errorf("decodeResponse = %+v, want %+v", quote, want)
+	}
+}
+
+func BenchmarkDecodeQuoteResponse(b *testing.B) {
+	checkFastDecode(b, benchmarkQuoteJSON, &QuoteResponse{}, &QuoteResponse{})
+	data := []byte(benchmarkQuoteJSON)
+
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var quote QuoteResponse
+			if err := decodeResponse(bytes.NewReader(data), &quote); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encoding_json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var quote QuoteResponse
+			if err := json.NewDecoder(bytes.NewReader(data)).Decode(&quote); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecodeOrderResponse(b *testing.B) {
+	checkFastDecode(b, benchmarkOrderJSON, &OrderResponse{}, &OrderResponse{})
+	data := []byte(benchmarkOrderJSON)
+
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var order OrderResponse
+			if err := decodeResponse(bytes.NewReader(data), &order); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encoding_json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var order OrderResponse
+			if err := json.NewDecoder(bytes.NewReader(data)).Decode(&order); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkGetQuote measures a whole GetQuote round trip against a local server. Its
+// allocations include the in-process server's.
+func BenchmarkGetQuote(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(benchmarkQuoteJSON))
+	}))
+	defer server.Close()
+
+	client := NewRestClient(RestClientConfig{
+		BaseURL:    server.URL,
+		APIKey:     "key",
+		Secret:     "c2VjcmV0",
+		Passphrase: "passphrase",
+	})
+	request := QuoteRequest{
+		TokenPair: TokenPair{BaseToken: "BTC", QuoteToken: "USD"},
+		Quantity:  Quantity{Token: "BTC", Value: 10},
+		Side:      "buy",
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.GetQuote(request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"time"
+)
+
+const statusSimulated = "simulated"
+
+// ErrDryRun is returned with the simulated response of PlaceOrder and ExecuteQuote in
+// dry-run mode: the request was signed but not sent, so nothing was filled.
+var ErrDryRun = errors.New("dry run: request signed but not sent")
+
+// DryRunRequest is a fully signed request that dry-run mode did not send.
+type DryRunRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+// dryRun builds and signs the request exactly as Request would, then hands it to the
+// configured handler, or logs it when there is none.
+func (client *RestClient) dryRun(method string, url string, params interface{}) error {
+	req, err := client.NewRequest(method, url, params)
+	if err != nil {
+		return err
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	headers := make(map[string]string, len(req.Header))
+	for k := range req.Header {
+		headers[k] = req.Header.Get(k)
+	}
+
+	request := DryRunRequest{Method: method, URL: req.URL.String(), Headers: headers, Body: string(body)}
+	if client.Config.DryRunHandler != nil {
+		client.Config.DryRunHandler(request)
+		return nil
+	}
+	log.Printf("dry run: %s %s %s", request.Method, request.URL, request.Body)
+	return nil
+}
+
+func simulatedOrder(orderParams OrderRequest) OrderResponse {
+	now := time.Now().UTC()
+	var limitPrice NullFloat64
+	if orderParams.OrderType == "limit" {
+		limitPrice = NewNullFloat64(orderParams.LimitPrice)
+	}
+	return OrderResponse{
+		Status:        statusSimulated,
+		TokenPair:     orderParams.TokenPair,
+		Quantity:      orderParams.Quantity,
+		SideRequested: orderParams.Side,
+		QuoteTime:     now,
+		OrderType:     orderParams.OrderType,
+		TimeInForce:   orderParams.TimeInForce,
+		LimitPrice:    limitPrice,
+		SlippageBps:   orderParams.SlippageBps,
+		ClientOrderId: orderParams.ClientOrderId,
+		Simulated:     true,
+	}
+}
+
+func simulatedExecution(quoteParams QuoteExecutionRequest) QuoteResponse {
+	return QuoteResponse{
+		Status:        statusSimulated,
+		FxQuoteId:     quoteParams.FxQuoteId,
+		SideRequested: quoteParams.Side,
+		Simulated:     true,
+	}
+}
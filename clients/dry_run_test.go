@@ -0,0 +1,90 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// dryRunClient returns a dry-run client whose server answers GetQuote and
+// GetQuoteStatus, and counts the trading requests that reached it.
+func dryRunClient(t *testing.T) *clients.RestClient {
+	var sent int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/quotes":
+			w.Write([]byte(`{"status": "success", "fx_quote_id": "q1", "buy_price": "100",
+				"side_requested": "buy", "t_expiry": "` + time.Now().Add(time.Minute).UTC().Format(time.RFC3339) + `"}`))
+		case "/v1/quotes/q1":
+			w.Write([]byte(`{"status": "success", "fx_quote_id": "q1", "is_filled": false}`))
+		default:
+			atomic.AddInt32(&sent, 1)
+			w.Write([]byte(`{"status": "success", "is_filled": true}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	var handled int32
+	client := clients.NewRestClient(clients.RestClientConfig{
+		BaseURL:       server.URL,
+		APIKey:        "key",
+		Secret:        "c2VjcmV0",
+		Passphrase:    "passphrase",
+		DryRun:        true,
+		DryRunHandler: func(clients.DryRunRequest) { atomic.AddInt32(&handled, 1) },
+	})
+	t.Cleanup(func() {
+		if n := atomic.LoadInt32(&sent); n != 0 {
+			t.Errorf("%d trading requests sent in dry-run mode", n)
+		}
+		if atomic.LoadInt32(&handled) == 0 {
+			t.Error("DryRunHandler not called")
+		}
+	})
+	return client
+}
+
+func TestQuoteAndExecuteDryRun(t *testing.T) {
+	client := dryRunClient(t)
+
+	start := time.Now()
+	result, err := client.QuoteAndExecute(context.Background(),
+		clients.QuoteRequest{Side: "buy"}, clients.AcceptancePolicy{})
+	if !errors.Is(err, clients.ErrDryRun) {
+		t.Fatalf("err = %v, want ErrDryRun", err)
+	}
+	if !result.Execution.Simulated || result.Quote.FxQuoteId != "q1" {
+		t.Errorf("result = %+v, want the quote and a simulated execution", result)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("QuoteAndExecute took %v, want no status polling", elapsed)
+	}
+}
+
+func TestOrderManagerDryRun(t *testing.T) {
+	client := dryRunClient(t)
+	manager := clients.NewOrderManager(client, clients.OrderManagerConfig{})
+
+	order, err := manager.PlaceOrder(context.Background(), clients.OrderRequest{Side: "buy", OrderType: "market"})
+	if !errors.Is(err, clients.ErrDryRun) {
+		t.Fatalf("err = %v, want ErrDryRun", err)
+	}
+	if order.State != clients.OrderSimulated {
+		t.Errorf("State = %s, want %s", order.State, clients.OrderSimulated)
+	}
+
+	quote, err := manager.GetQuote(context.Background(), clients.QuoteRequest{Side: "buy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executed, err := manager.ExecuteQuote(context.Background(), quote.ClientOrderId, "buy")
+	if !errors.Is(err, clients.ErrDryRun) || executed.State != clients.OrderSimulated {
+		t.Errorf("ExecuteQuote = %s, %v; want %s, ErrDryRun", executed.State, err, clients.OrderSimulated)
+	}
+}
@@ -0,0 +1,273 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgePercentile   = 0.95
+	defaultHedgeInitialDelay = 100 * time.Millisecond
+	defaultHedgeMinDelay     = 5 * time.Millisecond
+	defaultHedgeMaxDelay     = time.Second
+	defaultHedgeWindow       = 200
+	defaultHedgeMinSamples   = 20
+	defaultHedgeLoserTTL     = time.Minute
+)
+
+// ErrHedgeLoser is returned by HedgedQuoter.ExecuteQuote for a quote that lost a
+// hedged race. Only the quote returned by GetQuote may be executed.
+var ErrHedgeLoser = errors.New("quote lost a hedged request and must not be executed")
+
+// HedgeConfig configures a HedgedQuoter.
+type HedgeConfig struct {
+	// Percentile of recent latencies after which a second request is sent. Defaults to
+	// 0.95, hedging roughly the slowest 5% of calls.
+	Percentile float64
+	// InitialDelay is used until MinSamples latencies were observed. Defaults to 100ms.
+	InitialDelay time.Duration
+	// MinDelay and MaxDelay bound the hedge delay. Default to 5ms and 1s.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// Window is the number of recent latencies kept. Defaults to 200.
+	Window int
+	// MinSamples is the number of latencies needed before Percentile is used. Defaults
+	// to 20.
+	MinSamples int
+	// LoserTTL is how long losing quote ids are remembered; it must outlive the quotes.
+	// Defaults to one minute.
+	LoserTTL time.Duration
+}
+
+// HedgeStats summarizes a HedgedQuoter's activity.
+type HedgeStats struct {
+	// Requests counts hedgeable calls and Hedged those for which a second request was
+	// sent.
+	Requests int
+	Hedged   int
+	// HedgeWins counts hedges that answered before the original request.
+	HedgeWins int
+	// Saved is the total latency saved by hedge wins: how much later the original
+	// request answered, for those that did.
+	Saved time.Duration
+	// Delay is the current hedge delay.
+	Delay time.Duration
+}
+
+// HedgeRate is the fraction of requests that were hedged.
+func (stats HedgeStats) HedgeRate() float64 {
+	if stats.Requests == 0 {
+		return 0
+	}
+	return float64(stats.Hedged) / float64(stats.Requests)
+}
+
+// HedgedQuoter is a Quoter that cuts the tail latency of GetQuote and GetQuoteStatus.
+// When a call has not answered within a delay taken from a percentile of recent
+// latencies, it sends the same request again and returns whichever succeeds first. The
+// hedge carries the same client_order_id. ExecuteQuote is passed through unhedged and
+// refuses quotes that lost a race, so a losing quote is never executed through it.
+type HedgedQuoter struct {
+	quoter Quoter
+	config HedgeConfig
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+	losers    map[string]time.Time
+	stats     HedgeStats
+}
+
+// NewHedgedQuoter hedges the calls of quoter.
+func NewHedgedQuoter(quoter Quoter, config HedgeConfig) *HedgedQuoter {
+	if config.Percentile <= 0 || config.Percentile >= 1 {
+		config.Percentile = defaultHedgePercentile
+	}
+	if config.InitialDelay <= 0 {
+		config.InitialDelay = defaultHedgeInitialDelay
+	}
+	if config.MinDelay <= 0 {
+		config.MinDelay = defaultHedgeMinDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaultHedgeMaxDelay
+	}
+	if config.Window <= 0 {
+		config.Window = defaultHedgeWindow
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaultHedgeMinSamples
+	}
+	if config.LoserTTL <= 0 {
+		config.LoserTTL = defaultHedgeLoserTTL
+	}
+	return &HedgedQuoter{
+		quoter:    quoter,
+		config:    config,
+		latencies: make([]time.Duration, 0, config.Window),
+		losers:    make(map[string]time.Time),
+	}
+}
+
+var _ Quoter = (*HedgedQuoter)(nil)
+
+// GetQuote requests a quote, hedging it when the first request is slow. The losing
+// quote is discarded and remembered so ExecuteQuote refuses it.
+func (hedged *HedgedQuoter) GetQuote(quoteParams QuoteRequest) (QuoteResponse, error) {
+	return hedged.race(func() (QuoteResponse, error) {
+		return hedged.quoter.GetQuote(quoteParams)
+	}, true)
+}
+
+// GetQuoteStatus reads a quote's status, hedging it when the first request is slow.
+func (hedged *HedgedQuoter) GetQuoteStatus(fxQuoteID string) (QuoteResponse, error) {
+	return hedged.race(func() (QuoteResponse, error) {
+		return hedged.quoter.GetQuoteStatus(fxQuoteID)
+	}, false)
+}
+
+// ExecuteQuote executes a quote unless it lost a hedged GetQuote.
+func (hedged *HedgedQuoter) ExecuteQuote(quoteParams QuoteExecutionRequest) (QuoteResponse, error) {
+	if hedged.lost(quoteParams.FxQuoteId) {
+		return QuoteResponse{}, fmt.Errorf("%w: %s", ErrHedgeLoser, quoteParams.FxQuoteId)
+	}
+	return hedged.quoter.ExecuteQuote(quoteParams)
+}
+
+// Stats returns the hedging counters and current delay.
+func (hedged *HedgedQuoter) Stats() HedgeStats {
+	hedged.mu.Lock()
+	defer hedged.mu.Unlock()
+	stats := hedged.stats
+	stats.Delay = hedged.delayLocked()
+	return stats
+}
+
+// attempt is the outcome of one request in a race.
+type attempt struct {
+	hedge    bool
+	response QuoteResponse
+	err      error
+	done     time.Time
+}
+
+// race runs call and, if it is slower than the hedge delay, a second copy of it. It
+// returns the first success, or the original request's error when both fail. When
+// quotes is set the loser's quote id is remembered.
+func (hedged *HedgedQuoter) race(call func() (QuoteResponse, error), quotes bool) (QuoteResponse, error) {
+	hedged.mu.Lock()
+	hedged.stats.Requests++
+	delay := hedged.delayLocked()
+	hedged.mu.Unlock()
+
+	results := make(chan attempt, 2)
+	launch := func(hedge bool) {
+		start := time.Now()
+		response, err := call()
+		done := time.Now()
+		if err == nil {
+			hedged.observe(done.Sub(start))
+		}
+		results <- attempt{hedge: hedge, response: response, err: err, done: done}
+	}
+	go launch(false)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var first attempt
+	select {
+	case first = <-results:
+		// Answered before the delay: no hedge.
+		return first.response, first.err
+	case <-timer.C:
+	}
+
+	hedged.mu.Lock()
+	hedged.stats.Hedged++
+	hedged.mu.Unlock()
+	go launch(true)
+
+	first = <-results
+	if first.err != nil {
+		second := <-results
+		if second.err != nil {
+			if first.hedge {
+				return second.response, second.err
+			}
+			return first.response, first.err
+		}
+		hedged.settle(second, attempt{}, false)
+		return second.response, nil
+	}
+
+	// The other request is still in flight; settle it in the background so the caller
+	// is not kept waiting.
+	go func() {
+		hedged.settle(first, <-results, quotes)
+	}()
+	return first.response, nil
+}
+
+// settle records a finished race: who won, how much a hedge win saved, and the losing
+// quote.
+func (hedged *HedgedQuoter) settle(winner, loser attempt, quotes bool) {
+	hedged.mu.Lock()
+	defer hedged.mu.Unlock()
+
+	if winner.hedge {
+		hedged.stats.HedgeWins++
+		if !loser.done.IsZero() && loser.err == nil {
+			hedged.stats.Saved += loser.done.Sub(winner.done)
+		}
+	}
+	if quotes && loser.err == nil && loser.response.FxQuoteId != "" &&
+		loser.response.FxQuoteId != winner.response.FxQuoteId {
+		now := time.Now()
+		for id, at := range hedged.losers {
+			if now.Sub(at) > hedged.config.LoserTTL {
+				delete(hedged.losers, id)
+			}
+		}
+		hedged.losers[loser.response.FxQuoteId] = now
+	}
+}
+
+func (hedged *HedgedQuoter) lost(fxQuoteID string) bool {
+	hedged.mu.Lock()
+	defer hedged.mu.Unlock()
+	at, ok := hedged.losers[fxQuoteID]
+	return ok && time.Since(at) <= hedged.config.LoserTTL
+}
+
+func (hedged *HedgedQuoter) observe(latency time.Duration) {
+	hedged.mu.Lock()
+	defer hedged.mu.Unlock()
+	if len(hedged.latencies) < hedged.config.Window {
+		hedged.latencies = append(hedged.latencies, latency)
+		return
+	}
+	hedged.latencies[hedged.next] = latency
+	hedged.next = (hedged.next + 1) % hedged.config.Window
+}
+
+// delayLocked returns the configured percentile of recent latencies, clamped to
+// [MinDelay, MaxDelay]. Callers must hold hedged.mu.
+func (hedged *HedgedQuoter) delayLocked() time.Duration {
+	if len(hedged.latencies) < hedged.config.MinSamples {
+		return hedged.config.InitialDelay
+	}
+	sorted := append([]time.Duration(nil), hedged.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	delay := sorted[int(hedged.config.Percentile*float64(len(sorted)-1))]
+	if delay < hedged.config.MinDelay {
+		delay = hedged.config.MinDelay
+	}
+	if delay > hedged.config.MaxDelay {
+		delay = hedged.config.MaxDelay
+	}
+	return delay
+}
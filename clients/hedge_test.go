@@ -0,0 +1,189 @@
+package clients_test
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/clients/mocks"
+)
+
+// racingQuoter answers the n-th GetQuote, counting from zero, as answer(n) says, and
+// executes any quote.
+func racingQuoter(answer func(n int32) (clients.QuoteResponse, error)) *mocks.QuoterMock {
+	var calls int32
+	return &mocks.QuoterMock{
+		GetQuoteFunc: func(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+			return answer(atomic.AddInt32(&calls, 1) - 1)
+		},
+		ExecuteQuoteFunc: func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+			return clients.QuoteResponse{FxQuoteId: quoteParams.FxQuoteId, IsFilled: true}, nil
+		},
+	}
+}
+
+// waitFor polls condition until it holds, failing the test after a few seconds.
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHedgedQuoterFastCallIsNotHedged(t *testing.T) {
+	quoter := racingQuoter(func(n int32) (clients.QuoteResponse, error) {
+		return clients.QuoteResponse{FxQuoteId: "q0"}, nil
+	})
+	hedged := clients.NewHedgedQuoter(quoter, clients.HedgeConfig{InitialDelay: time.Second})
+
+	quote, err := hedged.GetQuote(clients.QuoteRequest{ClientOrderId: "c1"})
+	if err != nil || quote.FxQuoteId != "q0" {
+		t.Fatalf("GetQuote = %s, %v; want q0", quote.FxQuoteId, err)
+	}
+	if n := len(quoter.GetQuoteCalls()); n != 1 {
+		t.Errorf("%d requests sent, want 1", n)
+	}
+	if stats := hedged.Stats(); stats.Requests != 1 || stats.Hedged != 0 {
+		t.Errorf("stats = %+v, want one unhedged request", stats)
+	}
+}
+
+func TestHedgedQuoterHedgeWins(t *testing.T) {
+	release := make(chan struct{})
+	quoter := racingQuoter(func(n int32) (clients.QuoteResponse, error) {
+		if n == 0 {
+			<-release
+		}
+		return clients.QuoteResponse{FxQuoteId: fmt.Sprintf("q%d", n)}, nil
+	})
+	hedged := clients.NewHedgedQuoter(quoter, clients.HedgeConfig{InitialDelay: 10 * time.Millisecond})
+
+	quote, err := hedged.GetQuote(clients.QuoteRequest{ClientOrderId: "c1"})
+	if err != nil || quote.FxQuoteId != "q1" {
+		t.Fatalf("GetQuote = %s, %v; want the hedge's q1", quote.FxQuoteId, err)
+	}
+	for _, call := range quoter.GetQuoteCalls() {
+		if call.QuoteParams.ClientOrderId != "c1" {
+			t.Errorf("request sent with client order id %q, want c1", call.QuoteParams.ClientOrderId)
+		}
+	}
+
+	close(release)
+	waitFor(t, "the original request to settle", func() bool { return hedged.Stats().HedgeWins == 1 })
+	if _, err := hedged.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: "q0", Side: "buy"}); !errors.Is(err, clients.ErrHedgeLoser) {
+		t.Errorf("executing the loser = %v, want ErrHedgeLoser", err)
+	}
+	if _, err := hedged.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: "q1", Side: "buy"}); err != nil {
+		t.Errorf("executing the winner: %v", err)
+	}
+	if n := len(quoter.ExecuteQuoteCalls()); n != 1 {
+		t.Errorf("%d quotes executed, want only the winner", n)
+	}
+
+	stats := hedged.Stats()
+	if stats.Requests != 1 || stats.Hedged != 1 || stats.HedgeWins != 1 || stats.Saved <= 0 {
+		t.Errorf("stats = %+v, want one hedged request won by the hedge with latency saved", stats)
+	}
+}
+
+func TestHedgedQuoterOriginalWins(t *testing.T) {
+	hedgeSent := make(chan struct{})
+	releaseHedge := make(chan struct{})
+	quoter := racingQuoter(func(n int32) (clients.QuoteResponse, error) {
+		if n == 0 {
+			// Answer only once the hedge is in flight.
+			<-hedgeSent
+		} else {
+			close(hedgeSent)
+			<-releaseHedge
+		}
+		return clients.QuoteResponse{FxQuoteId: fmt.Sprintf("q%d", n)}, nil
+	})
+	// The loser is only known once it answers; until then executions reach FalconX.
+	quoter.ExecuteQuoteFunc = func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+		return clients.QuoteResponse{}, clients.Error{Code: 400, Reason: "not executable"}
+	}
+	hedged := clients.NewHedgedQuoter(quoter, clients.HedgeConfig{InitialDelay: 10 * time.Millisecond})
+
+	quote, err := hedged.GetQuote(clients.QuoteRequest{})
+	if err != nil || quote.FxQuoteId != "q0" {
+		t.Fatalf("GetQuote = %s, %v; want the original's q0", quote.FxQuoteId, err)
+	}
+
+	close(releaseHedge)
+	waitFor(t, "the hedge to be refused", func() bool {
+		_, err := hedged.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: "q1", Side: "buy"})
+		return errors.Is(err, clients.ErrHedgeLoser)
+	})
+	if stats := hedged.Stats(); stats.Hedged != 1 || stats.HedgeWins != 0 {
+		t.Errorf("stats = %+v, want one hedge that lost", stats)
+	}
+}
+
+func TestHedgedQuoterFailures(t *testing.T) {
+	original := errors.New("original failed")
+	tests := []struct {
+		name    string
+		answers []error
+		wantErr error
+		wantID  string
+	}{
+		{"both fail", []error{original, errors.New("hedge failed")}, original, ""},
+		{"original fails", []error{original, nil}, nil, "q1"},
+		{"hedge fails", []error{nil, errors.New("hedge failed")}, nil, "q0"},
+	}
+	for _, test := range tests {
+		answers, wantErr, wantID := test.answers, test.wantErr, test.wantID
+		t.Run(test.name, func(t *testing.T) {
+			hedgeSent := make(chan struct{})
+			quoter := racingQuoter(func(n int32) (clients.QuoteResponse, error) {
+				if n == 0 {
+					<-hedgeSent
+				} else {
+					close(hedgeSent)
+				}
+				if err := answers[n]; err != nil {
+					return clients.QuoteResponse{}, err
+				}
+				return clients.QuoteResponse{FxQuoteId: fmt.Sprintf("q%d", n)}, nil
+			})
+			hedged := clients.NewHedgedQuoter(quoter, clients.HedgeConfig{InitialDelay: 10 * time.Millisecond})
+
+			quote, err := hedged.GetQuote(clients.QuoteRequest{})
+			if err != wantErr || quote.FxQuoteId != wantID {
+				t.Errorf("GetQuote = %q, %v; want %q, %v", quote.FxQuoteId, err, wantID, wantErr)
+			}
+		})
+	}
+}
+
+func TestHedgedQuoterDelayFollowsLatencies(t *testing.T) {
+	quoter := racingQuoter(func(n int32) (clients.QuoteResponse, error) {
+		return clients.QuoteResponse{FxQuoteId: fmt.Sprintf("q%d", n)}, nil
+	})
+	hedged := clients.NewHedgedQuoter(quoter, clients.HedgeConfig{
+		InitialDelay: time.Second,
+		MinDelay:     20 * time.Millisecond,
+		MinSamples:   5,
+	})
+
+	if delay := hedged.Stats().Delay; delay != time.Second {
+		t.Errorf("delay before samples = %v, want the initial 1s", delay)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := hedged.GetQuote(clients.QuoteRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Instant answers put the percentile below MinDelay.
+	if delay := hedged.Stats().Delay; delay != 20*time.Millisecond {
+		t.Errorf("delay after fast samples = %v, want MinDelay 20ms", delay)
+	}
+}
@@ -0,0 +1,286 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultHistoryWindow    = 24 * time.Hour
+	defaultHistoryMinWindow = time.Second
+	defaultHistoryMaxWindow = 30 * 24 * time.Hour
+	defaultHistoryCap       = 500
+)
+
+// ErrWindowTruncated is returned when a window of MinWindow still holds Cap results, so
+// the server may have dropped some of them and the window cannot be split further.
+var ErrWindowTruncated = errors.New("history: window truncated at minimum size")
+
+// HistoryConfig tunes how ExecutedQuotesIter and TransfersIter split a time range into
+// windows. The API takes second precision, so windows are whole seconds.
+type HistoryConfig struct {
+	// Window is the size of the first window. Defaults to one day.
+	Window time.Duration
+	// MinWindow and MaxWindow bound the window size. They default to one second and
+	// thirty days.
+	MinWindow time.Duration
+	MaxWindow time.Duration
+	// Cap is the result count at which a window is assumed to be truncated by the server.
+	// Such a window is halved and fetched again, and a window of MinWindow fails with
+	// ErrWindowTruncated; windows returning less than a quarter of Cap are doubled.
+	// Defaults to 500.
+	Cap int
+}
+
+func (config HistoryConfig) withDefaults() HistoryConfig {
+	if config.MinWindow < time.Second {
+		config.MinWindow = defaultHistoryMinWindow
+	}
+	if config.MaxWindow <= 0 {
+		config.MaxWindow = defaultHistoryMaxWindow
+	}
+	if config.MaxWindow < config.MinWindow {
+		config.MaxWindow = config.MinWindow
+	}
+	if config.Window <= 0 {
+		config.Window = defaultHistoryWindow
+	}
+	config.Window = clampWindow(config.Window, config.MinWindow, config.MaxWindow)
+	if config.Cap <= 0 {
+		config.Cap = defaultHistoryCap
+	}
+	return config
+}
+
+func clampWindow(window, min, max time.Duration) time.Duration {
+	window = window.Truncate(time.Second)
+	if window < min {
+		return min
+	}
+	if window > max {
+		return max
+	}
+	return window
+}
+
+// historyWindows walks [start, end) in adaptive windows.
+type historyWindows struct {
+	ctx    context.Context
+	config HistoryConfig
+	next   time.Time
+	end    time.Time
+	window time.Duration
+}
+
+func newHistoryWindows(ctx context.Context, start, end time.Time, config HistoryConfig) *historyWindows {
+	config = config.withDefaults()
+	return &historyWindows{
+		ctx:    ctx,
+		config: config,
+		next:   start.UTC().Truncate(time.Second),
+		end:    end.UTC(),
+		window: config.Window,
+	}
+}
+
+// advance fetches the next window. fetch returns how many results the window held and
+// must replace, not append to, whatever it buffered on a previous call, since a
+// truncated window is fetched again. advance returns false once the range is exhausted.
+func (windows *historyWindows) advance(fetch func(from, to time.Time) (int, error)) (bool, error) {
+	for windows.next.Before(windows.end) {
+		if err := windows.ctx.Err(); err != nil {
+			return false, err
+		}
+
+		from := windows.next
+		to := from.Add(windows.window)
+		if to.After(windows.end) {
+			to = windows.end
+		}
+
+		count, err := fetch(from, to)
+		canShrink := windows.window > windows.config.MinWindow
+		if err != nil {
+			// Large windows are the usual cause of server timeouts.
+			if canShrink && isAmbiguous(err) {
+				windows.shrink()
+				continue
+			}
+			return false, err
+		}
+		if count >= windows.config.Cap {
+			if !canShrink {
+				return false, fmt.Errorf("%w: %d results from %s to %s", ErrWindowTruncated,
+					count, from.Format(time.RFC3339), to.Format(time.RFC3339))
+			}
+			windows.shrink()
+			continue
+		}
+
+		windows.next = to
+		if count < windows.config.Cap/4 {
+			windows.window = clampWindow(windows.window*2, windows.config.MinWindow, windows.config.MaxWindow)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (windows *historyWindows) shrink() {
+	windows.window = clampWindow(windows.window/2, windows.config.MinWindow, windows.config.MaxWindow)
+}
+
+// QuoteIterator streams executed quotes window by window. Quotes repeated across window
+// boundaries are returned once.
+//
+//	iter := client.ExecutedQuotesIter(ctx, start, end)
+//	for iter.Next() {
+//		quote := iter.Quote()
+//	}
+//	if err := iter.Err(); err != nil {
+//		...
+//	}
+type QuoteIterator struct {
+	account  Account
+	windows  *historyWindows
+	buffer   []QuoteResponse
+	current  QuoteResponse
+	previous map[string]bool
+	err      error
+}
+
+// ExecutedQuotesIter returns an iterator over the quotes executed in [start, end).
+func (client *RestClient) ExecutedQuotesIter(ctx context.Context, start, end time.Time) *QuoteIterator {
+	return ExecutedQuotesIterWith(ctx, client, start, end, client.Config.History)
+}
+
+// ExecutedQuotesIterWith is ExecutedQuotesIter for any Account implementation.
+func ExecutedQuotesIterWith(ctx context.Context, account Account, start, end time.Time, config HistoryConfig) *QuoteIterator {
+	iter := &QuoteIterator{
+		account:  account,
+		windows:  newHistoryWindows(ctx, start, end, config),
+		previous: make(map[string]bool),
+	}
+	if !start.Before(end) {
+		iter.err = errors.New("history: start must be before end")
+	}
+	return iter
+}
+
+// Next advances to the next quote. It returns false at the end of the range or on error.
+func (iter *QuoteIterator) Next() bool {
+	for len(iter.buffer) == 0 {
+		if iter.err != nil {
+			return false
+		}
+		var window []QuoteResponse
+		ok, err := iter.windows.advance(func(from, to time.Time) (int, error) {
+			quotes, err := iter.account.GetExecutedQuotes(from, to)
+			window = quotes
+			return len(quotes), err
+		})
+		if err != nil {
+			iter.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+
+		// Only the previous window can overlap the current one, so the seen set never
+		// grows beyond two windows.
+		seen := make(map[string]bool, len(window))
+		for _, quote := range window {
+			if iter.previous[quote.FxQuoteId] || seen[quote.FxQuoteId] {
+				continue
+			}
+			seen[quote.FxQuoteId] = true
+			iter.buffer = append(iter.buffer, quote)
+		}
+		iter.previous = seen
+	}
+
+	iter.current = iter.buffer[0]
+	iter.buffer = iter.buffer[1:]
+	return true
+}
+
+// Quote returns the quote Next advanced to.
+func (iter *QuoteIterator) Quote() QuoteResponse {
+	return iter.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (iter *QuoteIterator) Err() error {
+	return iter.err
+}
+
+// TransferIterator streams transfers window by window. Transfers carry no ID, so each
+// window keeps only the transfers created within it.
+type TransferIterator struct {
+	account Account
+	windows *historyWindows
+	buffer  []Transfer
+	current Transfer
+	err     error
+}
+
+// TransfersIter returns an iterator over the transfers created in [start, end).
+func (client *RestClient) TransfersIter(ctx context.Context, start, end time.Time) *TransferIterator {
+	return TransfersIterWith(ctx, client, start, end, client.Config.History)
+}
+
+// TransfersIterWith is TransfersIter for any Account implementation.
+func TransfersIterWith(ctx context.Context, account Account, start, end time.Time, config HistoryConfig) *TransferIterator {
+	iter := &TransferIterator{account: account, windows: newHistoryWindows(ctx, start, end, config)}
+	if !start.Before(end) {
+		iter.err = errors.New("history: start must be before end")
+	}
+	return iter
+}
+
+// Next advances to the next transfer. It returns false at the end of the range or on error.
+func (iter *TransferIterator) Next() bool {
+	for len(iter.buffer) == 0 {
+		if iter.err != nil {
+			return false
+		}
+		var window []Transfer
+		var from, to time.Time
+		ok, err := iter.windows.advance(func(windowFrom, windowTo time.Time) (int, error) {
+			transfers, err := iter.account.GetTransfers(windowFrom, windowTo)
+			window, from, to = transfers, windowFrom, windowTo
+			return len(transfers), err
+		})
+		if err != nil {
+			iter.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+
+		for _, transfer := range window {
+			if transfer.CreateTime.Before(from) || !transfer.CreateTime.Before(to) {
+				continue
+			}
+			iter.buffer = append(iter.buffer, transfer)
+		}
+	}
+
+	iter.current = iter.buffer[0]
+	iter.buffer = iter.buffer[1:]
+	return true
+}
+
+// Transfer returns the transfer Next advanced to.
+func (iter *TransferIterator) Transfer() Transfer {
+	return iter.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (iter *TransferIterator) Err() error {
+	return iter.err
+}
@@ -0,0 +1,118 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/clients/mocks"
+)
+
+var historyStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// historyAccount serves one executed quote at each of times. Like the API, it includes
+// quotes on both window boundaries and returns at most limit of them.
+func historyAccount(times []time.Time, limit int) *mocks.AccountMock {
+	return &mocks.AccountMock{
+		GetExecutedQuotesFunc: func(tStart time.Time, tEnd time.Time) ([]clients.QuoteResponse, error) {
+			var quotes []clients.QuoteResponse
+			for i, t := range times {
+				if t.Before(tStart) || t.After(tEnd) {
+					continue
+				}
+				if len(quotes) == limit {
+					break
+				}
+				quotes = append(quotes, clients.QuoteResponse{
+					FxQuoteId:     string(rune('a' + i)),
+					ExecutionTime: clients.NewNullTime(t),
+				})
+			}
+			return quotes, nil
+		},
+	}
+}
+
+func collectQuotes(t *testing.T, iter *clients.QuoteIterator) []string {
+	t.Helper()
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Quote().FxQuoteId)
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func windowSizes(account *mocks.AccountMock) []time.Duration {
+	var sizes []time.Duration
+	for _, call := range account.GetExecutedQuotesCalls() {
+		sizes = append(sizes, call.TEnd.Sub(call.TStart))
+	}
+	return sizes
+}
+
+func TestExecutedQuotesIterShrinksAndDedups(t *testing.T) {
+	// One quote per second, so every window boundary holds a quote returned twice.
+	var times []time.Time
+	for i := 0; i < 10; i++ {
+		times = append(times, historyStart.Add(time.Duration(i)*time.Second))
+	}
+	account := historyAccount(times, 4)
+	config := clients.HistoryConfig{Window: 8 * time.Second, Cap: 4}
+
+	ids := collectQuotes(t, clients.ExecutedQuotesIterWith(context.Background(), account,
+		historyStart, historyStart.Add(10*time.Second), config))
+
+	if want := "abcdefghij"; strings.Join(ids, "") != want {
+		t.Errorf("quotes = %q, want %q", strings.Join(ids, ""), want)
+	}
+	sizes := windowSizes(account)
+	if sizes[0] != 8*time.Second || sizes[1] != 4*time.Second || sizes[2] != 2*time.Second {
+		t.Errorf("window sizes = %v, want 8s, 4s, 2s first", sizes)
+	}
+}
+
+func TestExecutedQuotesIterGrows(t *testing.T) {
+	times := []time.Time{historyStart.Add(90 * time.Second)}
+	account := historyAccount(times, 8)
+	config := clients.HistoryConfig{Window: time.Second, Cap: 8}
+
+	ids := collectQuotes(t, clients.ExecutedQuotesIterWith(context.Background(), account,
+		historyStart, historyStart.Add(2*time.Minute), config))
+
+	if len(ids) != 1 {
+		t.Errorf("quotes = %v, want one", ids)
+	}
+	sizes := windowSizes(account)
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		if sizes[i] != want {
+			t.Fatalf("window sizes = %v, want doubling from 1s", sizes)
+		}
+	}
+}
+
+func TestExecutedQuotesIterTruncated(t *testing.T) {
+	// Five quotes within one second cannot be fetched under a cap of four.
+	var times []time.Time
+	for i := 0; i < 5; i++ {
+		times = append(times, historyStart.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	account := historyAccount(times, 4)
+	config := clients.HistoryConfig{Window: 4 * time.Second, Cap: 4}
+
+	iter := clients.ExecutedQuotesIterWith(context.Background(), account,
+		historyStart, historyStart.Add(10*time.Second), config)
+	for iter.Next() {
+	}
+	if err := iter.Err(); !errors.Is(err, clients.ErrWindowTruncated) {
+		t.Fatalf("Err() = %v, want ErrWindowTruncated", err)
+	}
+	if sizes := windowSizes(account); sizes[len(sizes)-1] != time.Second {
+		t.Errorf("window sizes = %v, want to end at MinWindow", sizes)
+	}
+}
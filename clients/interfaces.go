@@ -0,0 +1,44 @@
+package clients
+
+import "time"
+
+//go:generate moq -out mocks/mocks.go -pkg mocks . Quoter Orderer Account ReferenceData FalconX
+
+// Quoter requests, executes and tracks RFQ quotes.
+type Quoter interface {
+	GetQuote(quoteParams QuoteRequest) (QuoteResponse, error)
+	ExecuteQuote(quoteParams QuoteExecutionRequest) (QuoteResponse, error)
+	GetQuoteStatus(fxQuoteID string) (QuoteResponse, error)
+}
+
+// Orderer places market and limit orders.
+type Orderer interface {
+	PlaceOrder(orderParams OrderRequest) (OrderResponse, error)
+}
+
+// Account reads balances and the account's trade and transfer history.
+type Account interface {
+	GetBalances() ([]Balance, error)
+	GetTotalBalances() ([]TotalBalance, error)
+	GetTransfers(tStart time.Time, tEnd time.Time) ([]Transfer, error)
+	GetExecutedQuotes(tStart time.Time, tEnd time.Time) ([]QuoteResponse, error)
+}
+
+// ReferenceData reads the pairs, sizes, limits and volume the account is allowed to trade.
+type ReferenceData interface {
+	GetTradingPairs() ([]TokenPair, error)
+	GetTradeSizes() ([]TradeSize, error)
+	GetTradeLimits(platform string) (TradeLimits, error)
+	GetTradeVolume(tStart time.Time, tEnd time.Time) (TradeVolume, error)
+}
+
+// FalconX is the full REST API surface. RestClient implements it, and so can fakes,
+// simulators and decorators.
+type FalconX interface {
+	Quoter
+	Orderer
+	Account
+	ReferenceData
+}
+
+var _ FalconX = (*RestClient)(nil)
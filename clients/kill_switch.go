@@ -0,0 +1,232 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrKillSwitchTripped is returned by trading calls while the kill switch is tripped.
+var ErrKillSwitchTripped = errors.New("kill switch tripped")
+
+// KillSwitch halts all trading once tripped. Share one instance between RestClient,
+// SocketClient, the risk engine and any running algorithms. When created with a path
+// the tripped state is persisted, so a restart does not silently clear it; only Reset
+// does.
+type KillSwitch struct {
+	path string
+
+	mu        sync.Mutex
+	tripped   bool
+	reason    string
+	trippedAt time.Time
+	done      chan struct{}
+	hooks     []func(reason string)
+}
+
+type killSwitchState struct {
+	Tripped   bool      `json:"tripped"`
+	Reason    string    `json:"reason"`
+	TrippedAt time.Time `json:"tripped_at"`
+}
+
+// NewKillSwitch creates a kill switch persisted at path, restoring a previously tripped
+// state. An empty path keeps the state in memory only.
+func NewKillSwitch(path string) (*KillSwitch, error) {
+	killSwitch := &KillSwitch{path: path, done: make(chan struct{})}
+	if path == "" {
+		return killSwitch, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return killSwitch, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kill switch: reading %s: %w", path, err)
+	}
+
+	var state killSwitchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("kill switch: parsing %s: %w", path, err)
+	}
+	if state.Tripped {
+		killSwitch.tripped = true
+		killSwitch.reason = state.Reason
+		killSwitch.trippedAt = state.TrippedAt
+		close(killSwitch.done)
+	}
+	return killSwitch, nil
+}
+
+// Trip halts trading. Tripping an already tripped switch keeps the original reason.
+// The state is persisted before OnTrip hooks run; a persistence error is returned but
+// the switch is tripped regardless.
+func (killSwitch *KillSwitch) Trip(reason string) error {
+	killSwitch.mu.Lock()
+	if killSwitch.tripped {
+		killSwitch.mu.Unlock()
+		return nil
+	}
+	killSwitch.tripped = true
+	killSwitch.reason = reason
+	killSwitch.trippedAt = time.Now().UTC()
+	close(killSwitch.done)
+	err := killSwitch.persist()
+	hooks := append([]func(string){}, killSwitch.hooks...)
+	killSwitch.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(reason)
+	}
+	return err
+}
+
+// Reset re-enables trading and clears the persisted state.
+func (killSwitch *KillSwitch) Reset() error {
+	killSwitch.mu.Lock()
+	defer killSwitch.mu.Unlock()
+
+	if !killSwitch.tripped {
+		return nil
+	}
+	killSwitch.tripped = false
+	killSwitch.reason = ""
+	killSwitch.trippedAt = time.Time{}
+	killSwitch.done = make(chan struct{})
+	return killSwitch.persist()
+}
+
+// Tripped reports whether trading is halted. A nil KillSwitch is never tripped.
+func (killSwitch *KillSwitch) Tripped() bool {
+	if killSwitch == nil {
+		return false
+	}
+	killSwitch.mu.Lock()
+	defer killSwitch.mu.Unlock()
+	return killSwitch.tripped
+}
+
+// Reason returns why the switch was tripped and when.
+func (killSwitch *KillSwitch) Reason() (string, time.Time) {
+	killSwitch.mu.Lock()
+	defer killSwitch.mu.Unlock()
+	return killSwitch.reason, killSwitch.trippedAt
+}
+
+// Check returns an error wrapping ErrKillSwitchTripped while tripped. A nil KillSwitch
+// always passes.
+func (killSwitch *KillSwitch) Check() error {
+	if killSwitch == nil {
+		return nil
+	}
+	killSwitch.mu.Lock()
+	defer killSwitch.mu.Unlock()
+	if killSwitch.tripped {
+		return fmt.Errorf("%w: %s", ErrKillSwitchTripped, killSwitch.reason)
+	}
+	return nil
+}
+
+// Done returns a channel that is closed when the switch trips. A nil KillSwitch returns
+// a nil channel, which never fires.
+func (killSwitch *KillSwitch) Done() <-chan struct{} {
+	if killSwitch == nil {
+		return nil
+	}
+	killSwitch.mu.Lock()
+	defer killSwitch.mu.Unlock()
+	return killSwitch.done
+}
+
+// OnTrip registers hook to run every time the switch trips, e.g. to cancel an algorithm
+// or unsubscribe streams.
+func (killSwitch *KillSwitch) OnTrip(hook func(reason string)) {
+	killSwitch.mu.Lock()
+	defer killSwitch.mu.Unlock()
+	killSwitch.hooks = append(killSwitch.hooks, hook)
+}
+
+// TripOnSignal trips the switch when one of signals is received. Call the returned
+// function to stop listening.
+func (killSwitch *KillSwitch) TripOnSignal(signals ...os.Signal) (stop func()) {
+	received := make(chan os.Signal, 1)
+	quit := make(chan struct{})
+	signal.Notify(received, signals...)
+
+	go func() {
+		select {
+		case sig := <-received:
+			_ = killSwitch.Trip(fmt.Sprintf("signal %s", sig))
+		case <-quit:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(quit)
+		})
+	}
+}
+
+// WatchFile trips the switch once a file appears at sentinel, checking every interval
+// until ctx is done. Operators can halt trading with a plain touch.
+func (killSwitch *KillSwitch) WatchFile(ctx context.Context, sentinel string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := os.Stat(sentinel); err == nil {
+			_ = killSwitch.Trip(fmt.Sprintf("sentinel file %s", sentinel))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// persist writes the state atomically. Callers must hold killSwitch.mu.
+func (killSwitch *KillSwitch) persist() error {
+	if killSwitch.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(killSwitchState{
+		Tripped:   killSwitch.tripped,
+		Reason:    killSwitch.reason,
+		TrippedAt: killSwitch.trippedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(killSwitch.path), ".killswitch-")
+	if err != nil {
+		return fmt.Errorf("kill switch: persisting state: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("kill switch: persisting state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("kill switch: persisting state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), killSwitch.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("kill switch: persisting state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "falconx-killswitch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestKillSwitchPersistsAcrossReload(t *testing.T) {
+	path := filepath.Join(tempDir(t), "killswitch.json")
+
+	killSwitch, err := clients.NewKillSwitch(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if killSwitch.Tripped() {
+		t.Fatal("new kill switch is tripped")
+	}
+	var hooked []string
+	killSwitch.OnTrip(func(reason string) { hooked = append(hooked, reason) })
+
+	if err := killSwitch.Trip("drawdown"); err != nil {
+		t.Fatal(err)
+	}
+	if err := killSwitch.Trip("second"); err != nil {
+		t.Fatal(err)
+	}
+	if len(hooked) != 1 || hooked[0] != "drawdown" {
+		t.Errorf("OnTrip hooks ran with %v, want only drawdown", hooked)
+	}
+	select {
+	case <-killSwitch.Done():
+	default:
+		t.Error("Done not closed after Trip")
+	}
+	_, trippedAt := killSwitch.Reason()
+
+	reloaded, err := clients.NewKillSwitch(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.Tripped() {
+		t.Fatal("reloaded kill switch is not tripped")
+	}
+	if reason, at := reloaded.Reason(); reason != "drawdown" || !at.Equal(trippedAt) {
+		t.Errorf("reloaded reason %q at %v, want drawdown at %v", reason, at, trippedAt)
+	}
+	if err := reloaded.Check(); !errors.Is(err, clients.ErrKillSwitchTripped) {
+		t.Errorf("Check = %v, want ErrKillSwitchTripped", err)
+	}
+	select {
+	case <-reloaded.Done():
+	default:
+		t.Error("Done not closed on a reloaded tripped switch")
+	}
+
+	if err := reloaded.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloaded.Check(); err != nil {
+		t.Errorf("Check after Reset = %v", err)
+	}
+	again, err := clients.NewKillSwitch(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Tripped() {
+		t.Error("kill switch tripped after Reset and reload")
+	}
+}
+
+func TestKillSwitchCorruptState(t *testing.T) {
+	path := filepath.Join(tempDir(t), "killswitch.json")
+	if err := ioutil.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clients.NewKillSwitch(path); err == nil {
+		t.Error("NewKillSwitch accepted a corrupt state file")
+	}
+}
+
+func TestKillSwitchWatchFile(t *testing.T) {
+	sentinel := filepath.Join(tempDir(t), "halt")
+	killSwitch, err := clients.NewKillSwitch("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go killSwitch.WatchFile(ctx, sentinel, time.Millisecond)
+
+	if err := ioutil.WriteFile(sentinel, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-killSwitch.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("sentinel file did not trip the kill switch")
+	}
+}
+
+func TestNilKillSwitch(t *testing.T) {
+	var killSwitch *clients.KillSwitch
+	if killSwitch.Tripped() || killSwitch.Check() != nil || killSwitch.Done() != nil {
+		t.Error("nil kill switch is not permanently untripped")
+	}
+}
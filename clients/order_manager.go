@@ -0,0 +1,354 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOrderTimeout     = 10 * time.Second
+	defaultResolveLookback  = time.Minute
+	defaultWaitPollInterval = time.Second
+)
+
+// OrderState is the lifecycle state of an order tracked by OrderManager.
+type OrderState string
+
+const (
+	// OrderPending means the order was submitted and no answer has arrived yet, or the
+	// quote was issued but not executed.
+	OrderPending OrderState = "pending"
+	// OrderFilled means FalconX confirmed the fill.
+	OrderFilled OrderState = "filled"
+	// OrderRejected means FalconX refused the order or did not fill it.
+	OrderRejected OrderState = "rejected"
+	// OrderUnknown means no answer arrived within the timeout, or the answer was ambiguous.
+	OrderUnknown OrderState = "unknown"
+	// OrderSimulated means the client is in dry-run mode and the order was not sent.
+	OrderSimulated OrderState = "simulated"
+)
+
+// IsTerminal reports whether the order is settled. Only a late fill can still move a
+// rejected order to OrderFilled.
+func (state OrderState) IsTerminal() bool {
+	return state == OrderFilled || state == OrderRejected || state == OrderSimulated
+}
+
+// ErrOrderNotFound is returned when no order is tracked under a client order ID.
+var ErrOrderNotFound = errors.New("client order id not tracked")
+
+// ManagedOrder is the state OrderManager keeps for one client order ID.
+type ManagedOrder struct {
+	ClientOrderId string
+	State         OrderState
+	FxQuoteId     string
+	// Order is set for PlaceOrder submissions, Quote for RFQ submissions.
+	Order       *OrderRequest
+	Quote       *QuoteRequest
+	Response    QuoteResponse
+	Err         error
+	SubmittedAt time.Time
+	UpdatedAt   time.Time
+}
+
+// OrderManagerConfig configures an OrderManager.
+type OrderManagerConfig struct {
+	// NewID generates client order IDs. Defaults to NewUUID; NewULID is also available.
+	NewID func() string
+	// Timeout after which a submission without an answer becomes OrderUnknown. Defaults to 10s.
+	Timeout time.Duration
+	// ResolveLookback widens the GetExecutedQuotes window used to find unknown orders.
+	// Defaults to one minute.
+	ResolveLookback time.Duration
+}
+
+// OrderManager assigns client order IDs and tracks every order it submits until its
+// state is known.
+type OrderManager struct {
+	client FalconX
+	config OrderManagerConfig
+
+	mu     sync.Mutex
+	orders map[string]*trackedOrder
+}
+
+type trackedOrder struct {
+	order ManagedOrder
+	done  chan struct{}
+	// executing is set once ExecuteQuote has claimed the quote, so it is sent only once.
+	executing bool
+}
+
+// NewOrderManager creates an OrderManager on top of client.
+func NewOrderManager(client FalconX, config OrderManagerConfig) *OrderManager {
+	if config.NewID == nil {
+		config.NewID = NewUUID
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultOrderTimeout
+	}
+	if config.ResolveLookback <= 0 {
+		config.ResolveLookback = defaultResolveLookback
+	}
+
+	return &OrderManager{
+		client: client,
+		config: config,
+		orders: make(map[string]*trackedOrder),
+	}
+}
+
+// PlaceOrder submits an order, generating its ClientOrderId when empty. It returns once
+// FalconX answers, or with state OrderUnknown after the timeout or when ctx is done.
+func (manager *OrderManager) PlaceOrder(ctx context.Context, orderParams OrderRequest) (ManagedOrder, error) {
+	if orderParams.ClientOrderId == "" {
+		orderParams.ClientOrderId = manager.config.NewID()
+	}
+	id := orderParams.ClientOrderId
+	request := orderParams
+	if err := manager.track(ManagedOrder{ClientOrderId: id, Order: &request}); err != nil {
+		return ManagedOrder{}, err
+	}
+
+	return manager.submit(ctx, id, func() (QuoteResponse, error) {
+		response, err := manager.client.PlaceOrder(orderParams)
+		return response.AsQuoteResponse(), err
+	})
+}
+
+// GetQuote requests a quote, generating its ClientOrderId when empty. The order stays
+// OrderPending until ExecuteQuote is called for the same client order ID.
+func (manager *OrderManager) GetQuote(ctx context.Context, quoteParams QuoteRequest) (ManagedOrder, error) {
+	if quoteParams.ClientOrderId == "" {
+		quoteParams.ClientOrderId = manager.config.NewID()
+	}
+	id := quoteParams.ClientOrderId
+	request := quoteParams
+	if err := manager.track(ManagedOrder{ClientOrderId: id, Quote: &request}); err != nil {
+		return ManagedOrder{}, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return manager.update(id, QuoteResponse{}, err, OrderRejected), err
+	}
+
+	quote, err := manager.client.GetQuote(quoteParams)
+	if err != nil {
+		return manager.update(id, quote, err, OrderRejected), err
+	}
+	return manager.update(id, quote, nil, OrderPending), nil
+}
+
+// ExecuteQuote executes the quote previously obtained through GetQuote for clientOrderId.
+// A quote is executed at most once: concurrent calls for the same order fail.
+func (manager *OrderManager) ExecuteQuote(ctx context.Context, clientOrderId string, side string) (ManagedOrder, error) {
+	manager.mu.Lock()
+	tracked, ok := manager.orders[clientOrderId]
+	if !ok || tracked.order.Quote == nil {
+		manager.mu.Unlock()
+		return ManagedOrder{}, fmt.Errorf("%w: %s", ErrOrderNotFound, clientOrderId)
+	}
+	order, executing := tracked.order, tracked.executing
+	tracked.executing = true
+	manager.mu.Unlock()
+	if order.State != OrderPending || order.FxQuoteId == "" {
+		return order, fmt.Errorf("order %s is %s and cannot be executed", clientOrderId, order.State)
+	}
+	if executing {
+		return order, fmt.Errorf("order %s is already being executed", clientOrderId)
+	}
+
+	return manager.submit(ctx, clientOrderId, func() (QuoteResponse, error) {
+		return manager.client.ExecuteQuote(QuoteExecutionRequest{FxQuoteId: order.FxQuoteId, Side: side})
+	})
+}
+
+// Get returns the tracked state for clientOrderId.
+func (manager *OrderManager) Get(clientOrderId string) (ManagedOrder, bool) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	tracked, ok := manager.orders[clientOrderId]
+	if !ok {
+		return ManagedOrder{}, false
+	}
+	return tracked.order, true
+}
+
+// Orders returns every tracked order, oldest first.
+func (manager *OrderManager) Orders() []ManagedOrder {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	result := make([]ManagedOrder, 0, len(manager.orders))
+	for _, tracked := range manager.orders {
+		result = append(result, tracked.order)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SubmittedAt.Before(result[j].SubmittedAt) })
+	return result
+}
+
+// Wait blocks until clientOrderId reaches a terminal state or ctx is done. Unknown orders
+// are resolved periodically while waiting.
+func (manager *OrderManager) Wait(ctx context.Context, clientOrderId string) (ManagedOrder, error) {
+	manager.mu.Lock()
+	tracked, ok := manager.orders[clientOrderId]
+	manager.mu.Unlock()
+	if !ok {
+		return ManagedOrder{}, fmt.Errorf("%w: %s", ErrOrderNotFound, clientOrderId)
+	}
+
+	ticker := time.NewTicker(defaultWaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-tracked.done:
+			order, _ := manager.Get(clientOrderId)
+			return order, nil
+		case <-ctx.Done():
+			order, _ := manager.Get(clientOrderId)
+			return order, ctx.Err()
+		case <-ticker.C:
+			if order, _ := manager.Get(clientOrderId); order.State == OrderUnknown {
+				_, _ = manager.Resolve(clientOrderId)
+			}
+		}
+	}
+}
+
+// Resolve settles an OrderUnknown order through GetQuoteStatus when its fx_quote_id is
+// known, or by searching GetExecutedQuotes for its client order ID otherwise. Rejected
+// orders are checked the same way, so a fill that arrived late moves them to OrderFilled.
+func (manager *OrderManager) Resolve(clientOrderId string) (ManagedOrder, error) {
+	order, ok := manager.Get(clientOrderId)
+	if !ok {
+		return ManagedOrder{}, fmt.Errorf("%w: %s", ErrOrderNotFound, clientOrderId)
+	}
+	if order.State != OrderUnknown && order.State != OrderRejected {
+		return order, nil
+	}
+
+	if order.FxQuoteId != "" {
+		status, err := manager.client.GetQuoteStatus(order.FxQuoteId)
+		if err != nil {
+			return order, err
+		}
+		switch {
+		case status.IsFilled:
+			return manager.update(clientOrderId, status, nil, OrderFilled), nil
+		case !status.ExpiryTime.IsZero() && time.Now().After(status.ExpiryTime):
+			return manager.update(clientOrderId, status, nil, OrderRejected), nil
+		}
+		return order, nil
+	}
+
+	lookback := manager.config.ResolveLookback
+	executed, err := manager.client.GetExecutedQuotes(order.SubmittedAt.Add(-lookback), time.Now().Add(lookback))
+	if err != nil {
+		return order, err
+	}
+	for _, quote := range executed {
+		if quote.ClientOrderId == clientOrderId && quote.IsFilled {
+			return manager.update(clientOrderId, quote, nil, OrderFilled), nil
+		}
+	}
+	if time.Since(order.SubmittedAt) > lookback {
+		return manager.update(clientOrderId, QuoteResponse{}, order.Err, OrderRejected), nil
+	}
+	return order, nil
+}
+
+func (manager *OrderManager) track(order ManagedOrder) error {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	if _, exists := manager.orders[order.ClientOrderId]; exists {
+		return fmt.Errorf("client order id %s already in use", order.ClientOrderId)
+	}
+	now := time.Now()
+	order.State = OrderPending
+	order.SubmittedAt = now
+	order.UpdatedAt = now
+	manager.orders[order.ClientOrderId] = &trackedOrder{order: order, done: make(chan struct{})}
+	return nil
+}
+
+// submit runs call in the background and waits for it up to the timeout. A late answer
+// still updates the order after submit returned OrderUnknown.
+func (manager *OrderManager) submit(ctx context.Context, id string, call func() (QuoteResponse, error)) (ManagedOrder, error) {
+	type answer struct {
+		response QuoteResponse
+		err      error
+	}
+	answers := make(chan answer, 1)
+	go func() {
+		response, err := call()
+		answers <- answer{response, err}
+	}()
+
+	timer := time.NewTimer(manager.config.Timeout)
+	defer timer.Stop()
+
+	var pending answer
+	select {
+	case pending = <-answers:
+		order := manager.update(id, pending.response, pending.err, classify(pending.response, pending.err))
+		return order, pending.err
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	order := manager.update(id, QuoteResponse{}, nil, OrderUnknown)
+	go func() {
+		late := <-answers
+		manager.update(id, late.response, late.err, classify(late.response, late.err))
+	}()
+	if err := ctx.Err(); err != nil {
+		return order, err
+	}
+	return order, nil
+}
+
+func (manager *OrderManager) update(id string, response QuoteResponse, err error, state OrderState) ManagedOrder {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+
+	tracked := manager.orders[id]
+	settled := tracked.order.State.IsTerminal()
+	if settled && !(tracked.order.State == OrderRejected && state == OrderFilled) {
+		return tracked.order
+	}
+
+	if response.FxQuoteId != "" {
+		tracked.order.FxQuoteId = response.FxQuoteId
+		tracked.order.Response = response
+	}
+	tracked.order.Err = err
+	tracked.order.State = state
+	tracked.order.UpdatedAt = time.Now()
+	if state.IsTerminal() && !settled {
+		close(tracked.done)
+	}
+	return tracked.order
+}
+
+func classify(response QuoteResponse, err error) OrderState {
+	if err != nil {
+		if errors.Is(err, ErrDryRun) {
+			return OrderSimulated
+		}
+		if isAmbiguous(err) {
+			return OrderUnknown
+		}
+		return OrderRejected
+	}
+	if response.IsFilled {
+		return OrderFilled
+	}
+	return OrderRejected
+}
@@ -0,0 +1,94 @@
+package clients_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/clients/mocks"
+)
+
+func quotingClient() *mocks.FalconXMock {
+	return &mocks.FalconXMock{
+		GetQuoteFunc: func(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+			return clients.QuoteResponse{FxQuoteId: "q1", ClientOrderId: quoteParams.ClientOrderId,
+				ExpiryTime: time.Now().Add(time.Minute)}, nil
+		},
+	}
+}
+
+func TestOrderManagerExecutesQuoteOnce(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	client := quotingClient()
+	client.ExecuteQuoteFunc = func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+		entered <- struct{}{}
+		<-release
+		return clients.QuoteResponse{FxQuoteId: quoteParams.FxQuoteId, IsFilled: true}, nil
+	}
+	manager := clients.NewOrderManager(client, clients.OrderManagerConfig{})
+
+	ctx := context.Background()
+	if _, err := manager.GetQuote(ctx, clients.QuoteRequest{ClientOrderId: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := make(chan clients.ManagedOrder)
+	go func() {
+		order, _ := manager.ExecuteQuote(ctx, "c1", "buy")
+		first <- order
+	}()
+	<-entered
+
+	if _, err := manager.ExecuteQuote(ctx, "c1", "buy"); err == nil {
+		t.Error("second ExecuteQuote succeeded while the first was in flight")
+	}
+	close(release)
+
+	if order := <-first; order.State != clients.OrderFilled {
+		t.Errorf("state = %s, want filled", order.State)
+	}
+	if _, err := manager.ExecuteQuote(ctx, "c1", "buy"); err == nil {
+		t.Error("ExecuteQuote succeeded on a filled order")
+	}
+	if n := len(client.ExecuteQuoteCalls()); n != 1 {
+		t.Errorf("ExecuteQuote sent %d times, want 1", n)
+	}
+}
+
+func TestOrderManagerLateFillOverridesRejection(t *testing.T) {
+	client := quotingClient()
+	client.ExecuteQuoteFunc = func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+		return clients.QuoteResponse{FxQuoteId: quoteParams.FxQuoteId, IsFilled: false}, nil
+	}
+	client.GetQuoteStatusFunc = func(fxQuoteID string) (clients.QuoteResponse, error) {
+		return clients.QuoteResponse{FxQuoteId: fxQuoteID, IsFilled: true}, nil
+	}
+	manager := clients.NewOrderManager(client, clients.OrderManagerConfig{})
+
+	ctx := context.Background()
+	if _, err := manager.GetQuote(ctx, clients.QuoteRequest{ClientOrderId: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+	order, err := manager.ExecuteQuote(ctx, "c1", "buy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.State != clients.OrderRejected {
+		t.Fatalf("state = %s, want rejected", order.State)
+	}
+
+	order, err = manager.Resolve("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.State != clients.OrderFilled || !order.Response.IsFilled {
+		t.Errorf("state after Resolve = %s, want filled", order.State)
+	}
+
+	waited, err := manager.Wait(ctx, "c1")
+	if err != nil || waited.State != clients.OrderFilled {
+		t.Errorf("Wait = %s, %v, want filled", waited.State, err)
+	}
+}
@@ -0,0 +1,96 @@
+package clients
+
+import (
+	"sort"
+)
+
+// Sorted returns the ladder's levels ordered by increasing quantity.
+func (ladder PriceLadder) Sorted() []PriceLevel {
+	levels := make([]PriceLevel, len(ladder.Levels))
+	copy(levels, ladder.Levels)
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Quantity < levels[j].Quantity })
+	return levels
+}
+
+// Top returns the smallest-quantity level, which carries the best prices.
+func (ladder PriceLadder) Top() (PriceLevel, bool) {
+	levels := ladder.Sorted()
+	if len(levels) == 0 {
+		return PriceLevel{}, false
+	}
+	return levels[0], true
+}
+
+// Mid returns the mid of the top level.
+func (ladder PriceLadder) Mid() (float64, bool) {
+	top, ok := ladder.Top()
+	if !ok || top.BuyPrice <= 0 || top.SellPrice <= 0 {
+		return 0, false
+	}
+	return (top.BuyPrice + top.SellPrice) / 2, true
+}
+
+// MaxQuantity returns the largest quantity the ladder prices.
+func (ladder PriceLadder) MaxQuantity() float64 {
+	levels := ladder.Sorted()
+	if len(levels) == 0 {
+		return 0
+	}
+	return levels[len(levels)-1].Quantity
+}
+
+// PriceFor returns the price for trading quantity on side, buy or sell. Quantities up to
+// the first level get its price, quantities between levels are interpolated linearly and
+// quantities beyond the last level cannot be priced.
+func (ladder PriceLadder) PriceFor(side string, quantity float64) (float64, bool) {
+	levels := ladder.Sorted()
+	if len(levels) == 0 || quantity > levels[len(levels)-1].Quantity {
+		return 0, false
+	}
+
+	price := func(level PriceLevel) float64 {
+		if side == "sell" {
+			return level.SellPrice
+		}
+		return level.BuyPrice
+	}
+
+	if quantity <= levels[0].Quantity {
+		return price(levels[0]), price(levels[0]) > 0
+	}
+	for i := 1; i < len(levels); i++ {
+		lower, upper := levels[i-1], levels[i]
+		if quantity <= upper.Quantity {
+			weight := (quantity - lower.Quantity) / (upper.Quantity - lower.Quantity)
+			p := price(lower) + weight*(price(upper)-price(lower))
+			return p, p > 0
+		}
+	}
+	return 0, false
+}
+
+// ImpactBps returns how much worse, in basis points, trading quantity on side is than
+// the top level price.
+func (ladder PriceLadder) ImpactBps(side string, quantity float64) (float64, bool) {
+	top, ok := ladder.Top()
+	if !ok {
+		return 0, false
+	}
+	price, ok := ladder.PriceFor(side, quantity)
+	if !ok {
+		return 0, false
+	}
+
+	topPrice := top.BuyPrice
+	if side == "sell" {
+		topPrice = top.SellPrice
+	}
+	if topPrice <= 0 {
+		return 0, false
+	}
+	impact := (price - topPrice) / topPrice * 10000
+	if side == "sell" {
+		impact = -impact
+	}
+	return impact, true
+}
@@ -0,0 +1,66 @@
+package clients
+
+// ExecutedPrice returns the price the quote was filled at, or zero when it was not filled.
+func (quote QuoteResponse) ExecutedPrice() float64 {
+	if !quote.IsFilled {
+		return 0
+	}
+	if price, ok := quote.PriceExecuted.Get(); ok {
+		return price
+	}
+	if quote.SideExecuted.Side == "sell" {
+		return quote.SellPrice.Float64
+	}
+	return quote.BuyPrice.Float64
+}
+
+// ExecutedPrice returns the price the order was filled at, or zero when it was not filled.
+func (order OrderResponse) ExecutedPrice() float64 {
+	if !order.IsFilled {
+		return 0
+	}
+	if order.SideExecuted.Side == "sell" {
+		return order.SellPrice.Float64
+	}
+	return order.BuyPrice.Float64
+}
+
+// AsQuoteResponse returns the order in the shape GetQuoteStatus and GetExecutedQuotes
+// use. Order-type fields have no counterpart and are dropped.
+func (order OrderResponse) AsQuoteResponse() QuoteResponse {
+	return QuoteResponse{
+		Status:        order.Status,
+		FxQuoteId:     order.FxQuoteId,
+		BuyPrice:      order.BuyPrice,
+		SellPrice:     order.SellPrice,
+		Platform:      order.Platform,
+		TokenPair:     order.TokenPair,
+		Quantity:      order.Quantity,
+		SideRequested: order.SideRequested,
+		QuoteTime:     order.QuoteTime,
+		ExpiryTime:    order.ExpiryTime,
+		ExecutionTime: order.ExecutionTime,
+		IsFilled:      order.IsFilled,
+		SideExecuted:  order.SideExecuted,
+		PriceExecuted: order.executedPrice(),
+		GrossFeeBps:   order.GrossFeeBps,
+		GrossFeeUSD:   order.GrossFeeUSD,
+		RebateBps:     order.RebateBps,
+		RebateUSD:     order.RebateUSD,
+		FeeBps:        order.FeeBps,
+		FeeUSD:        order.FeeUSD,
+		TraderEmail:   order.TraderEmail,
+		Error:         order.Error,
+		Warnings:      order.Warnings,
+		ClientOrderId: order.ClientOrderId,
+		Simulated:     order.Simulated,
+	}
+}
+
+// executedPrice is ExecutedPrice as a NullFloat64, null when the order was not filled.
+func (order OrderResponse) executedPrice() NullFloat64 {
+	if !order.IsFilled {
+		return NullFloat64{}
+	}
+	return NewNullFloat64(order.ExecutedPrice())
+}
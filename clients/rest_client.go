@@ -5,8 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
+
+	"github.com/falconxio/falconx-go/auth"
 )
 
 type RestClient struct {
@@ -19,14 +20,34 @@ type RestClientConfig struct {
 	Secret     string
 	APIKey     string
 	Passphrase string
+	// Signer, when set, signs requests instead of Secret, which can then be left empty.
+	Signer Signer
+	// Credentials, when set, supplies the API key, passphrase and signer of every request
+	// instead of the fields above, so they can be rotated without rebuilding the client.
+	Credentials CredentialsProvider
+	// KillSwitch, when set, rejects PlaceOrder and ExecuteQuote while tripped.
+	KillSwitch *KillSwitch
+	// DryRun builds and signs PlaceOrder and ExecuteQuote requests but hands them to
+	// DryRunHandler instead of sending them; the calls then return a simulated response
+	// with ErrDryRun. Read endpoints are unaffected.
+	DryRun        bool
+	DryRunHandler func(DryRunRequest)
+	// History tunes the windows used by ExecutedQuotesIter and TransfersIter.
+	History HistoryConfig
+	// Clock corrects FX-ACCESS-TIMESTAMP for the skew between this host and FalconX.
+	// NewRestClient creates one when nil; share it with SocketClientConfig.Clock.
+	Clock *Clock
+	// Transport tunes connection pooling, timeouts and HTTP/2 of the HTTP client.
+	Transport TransportConfig
 }
 
 func NewRestClient(config RestClientConfig) *RestClient {
+	if config.Clock == nil {
+		config.Clock = NewClock(ClockConfig{})
+	}
 	client := RestClient{
-		Config: config,
-		HTTPClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		Config:     config,
+		HTTPClient: newHTTPClient(config.Transport),
 	}
 
 	return &client
@@ -34,40 +55,16 @@ func NewRestClient(config RestClientConfig) *RestClient {
 
 func (client *RestClient) Request(method string, url string,
 	params interface{}, result interface{}) (res *http.Response, err error) {
-	var data []byte
-	body := bytes.NewReader(make([]byte, 0))
-
-	if params != nil {
-		data, err = json.Marshal(params)
-		if err != nil {
+	res, err = client.send(method, url, params)
+	if err == nil && res.StatusCode == 401 && client.Config.Clock != nil {
+		// A skewed clock makes the signature look stale. Resync from this response and
+		// try once more with a fresh timestamp.
+		res.Body.Close()
+		if err = client.resyncClock(res); err != nil {
 			return res, err
 		}
-
-		body = bytes.NewReader(data)
-	}
-
-	fullURL := fmt.Sprintf("%s%s", client.Config.BaseURL, url)
-	req, err := http.NewRequest(method, fullURL, body)
-	if err != nil {
-		return res, err
-	}
-
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	dataString := ""
-	if len(data) > 0 {
-		dataString = string(data)
-	}
-
-	h, err := client.Headers(method, url, timestamp, dataString)
-	if err != nil {
-		return res, err
+		res, err = client.send(method, url, params)
 	}
-
-	for k, v := range h {
-		req.Header.Add(k, v)
-	}
-
-	res, err = client.HTTPClient.Do(req)
 	if err != nil {
 		return res, err
 	}
@@ -99,8 +96,7 @@ func (client *RestClient) Request(method string, url string,
 	}
 
 	if result != nil {
-		decoder := json.NewDecoder(res.Body)
-		if err = decoder.Decode(&result); err != nil {
+		if err = decodeResponse(res.Body, result); err != nil {
 			return res, err
 		}
 	}
@@ -108,28 +104,125 @@ func (client *RestClient) Request(method string, url string,
 	return res, err
 }
 
+// send signs and sends a request, measuring the clock offset from the response.
+func (client *RestClient) send(method string, url string, params interface{}) (*http.Response, error) {
+	req, err := client.NewRequest(method, url, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var trace *requestTrace
+	if client.Config.Transport.OnTiming != nil {
+		req, trace = traceRequest(req)
+	}
+
+	sent := time.Now()
+	res, err := client.HTTPClient.Do(req)
+	if trace != nil {
+		client.Config.Transport.OnTiming(trace.finish(res, err))
+	}
+	if err != nil {
+		return res, err
+	}
+	client.Config.Clock.Observe(res.Header.Get("Date"), sent, time.Now())
+	return res, nil
+}
+
+// resyncClock brings the clock up to date after res was rejected. send has already
+// measured the offset from its Date header; without one it takes a SyncClock round trip.
+func (client *RestClient) resyncClock(res *http.Response) error {
+	if res.Header.Get("Date") != "" {
+		return nil
+	}
+	return client.SyncClock()
+}
+
+// SyncClock measures the clock offset from FalconX with an unauthenticated HEAD
+// request. Request keeps the offset current on its own; call SyncClock at startup to
+// sign the first request with the server time.
+func (client *RestClient) SyncClock() error {
+	req, err := http.NewRequest("HEAD", client.Config.BaseURL, nil)
+	if err != nil {
+		return err
+	}
+	sent := time.Now()
+	res, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.Header.Get("Date") == "" {
+		return Error{Code: res.StatusCode, Reason: "No Date header to sync the clock from"}
+	}
+	client.Config.Clock.Observe(res.Header.Get("Date"), sent, time.Now())
+	return nil
+}
+
+// NewRequest builds a signed request for the endpoint url with params as its JSON body.
+func (client *RestClient) NewRequest(method string, url string, params interface{}) (*http.Request, error) {
+	var data []byte
+	var err error
+	body := bytes.NewReader(make([]byte, 0))
+
+	if params != nil {
+		data, err = json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	fullURL := fmt.Sprintf("%s%s", client.Config.BaseURL, url)
+	req, err := http.NewRequest(method, fullURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := auth.Timestamp(client.Config.Clock.Now())
+	dataString := ""
+	if len(data) > 0 {
+		dataString = string(data)
+	}
+
+	h, err := client.Headers(method, url, timestamp, dataString)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range h {
+		req.Header.Add(k, v)
+	}
+
+	return req, nil
+}
+
 // Headers generates a map that can be used as headers to authenticate a request
 func (client *RestClient) Headers(method, url, timestamp, data string) (map[string]string, error) {
-	header := make(map[string]string)
-	header["Content-Type"] = "application/json"
-	header["FX-ACCESS-KEY"] = client.Config.APIKey
-	header["FX-ACCESS-PASSPHRASE"] = client.Config.Passphrase
-	header["FX-ACCESS-TIMESTAMP"] = timestamp
-
-	message := fmt.Sprintf(
-		"%s%s%s%s",
-		timestamp,
-		method,
-		url,
-		data,
-	)
-
-	sig, err := GenerateSig(message, client.Config.Secret)
+	credentials, err := client.credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := credentials.Sign(auth.Prehash(timestamp, method, url, data))
 	if err != nil {
 		return nil, err
 	}
-	header["FX-ACCESS-SIGN"] = sig
-	return header, nil
+	return auth.Headers(credentials.APIKey, credentials.Passphrase, timestamp, sig), nil
+}
+
+// credentials returns the provider's current credentials, or the static ones from the
+// config when no provider is set.
+func (client *RestClient) credentials() (Credentials, error) {
+	if client.Config.Credentials != nil {
+		return client.Config.Credentials.Credentials()
+	}
+	return Credentials{
+		APIKey:     client.Config.APIKey,
+		Secret:     client.Config.Secret,
+		Passphrase: client.Config.Passphrase,
+		Signer:     client.Config.Signer,
+	}, nil
 }
 
 // GetTradingPairs gets a list of trading pairs you are eligible to trade
@@ -225,6 +318,15 @@ func (client *RestClient) GetQuote(quoteParams QuoteRequest) (QuoteResponse, err
 //             }
 func (client *RestClient) PlaceOrder(orderParams OrderRequest) (OrderResponse, error) {
 	var result OrderResponse
+	if err := client.Config.KillSwitch.Check(); err != nil {
+		return result, err
+	}
+	if client.Config.DryRun {
+		if err := client.dryRun("POST", "/v1/order", orderParams); err != nil {
+			return result, err
+		}
+		return simulatedOrder(orderParams), ErrDryRun
+	}
 	_, err := client.Request("POST", "/v1/order", orderParams, &result)
 	return result, err
 }
@@ -251,6 +353,15 @@ func (client *RestClient) PlaceOrder(orderParams OrderRequest) (OrderResponse, e
 //                 }
 func (client *RestClient) ExecuteQuote(quoteParams QuoteExecutionRequest) (QuoteResponse, error) {
 	var result QuoteResponse
+	if err := client.Config.KillSwitch.Check(); err != nil {
+		return result, err
+	}
+	if client.Config.DryRun {
+		if err := client.dryRun("POST", "/v1/quotes/execute", quoteParams); err != nil {
+			return result, err
+		}
+		return simulatedExecution(quoteParams), ErrDryRun
+	}
 	_, err := client.Request("POST", "/v1/quotes/execute", quoteParams, &result)
 	return result, err
 }
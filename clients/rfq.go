@@ -0,0 +1,269 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultExpirySafetyMargin = 250 * time.Millisecond
+	defaultStatusPollInterval = 250 * time.Millisecond
+	defaultConfirmWindow      = 5 * time.Second
+)
+
+var (
+	// ErrQuoteRejected is returned when the acceptance policy declines a quote.
+	ErrQuoteRejected = errors.New("quote rejected by acceptance policy")
+	// ErrQuoteExpired is returned when a quote is too close to t_expiry to execute safely.
+	ErrQuoteExpired = errors.New("quote too close to expiry")
+	// ErrQuoteNotFilled is returned when FalconX reports the quote as not filled.
+	ErrQuoteNotFilled = errors.New("quote not filled")
+	// ErrExecutionUnknown is returned when an execute call failed ambiguously and the
+	// fill could not be confirmed through GetQuoteStatus either.
+	ErrExecutionUnknown = errors.New("quote execution state unknown")
+)
+
+// AcceptancePolicy decides whether a quote is executed by QuoteAndExecute. Every
+// configured check must pass; zero values disable a check.
+type AcceptancePolicy struct {
+	// Side to execute. Required for two_way quotes, otherwise defaults to the requested side.
+	Side string
+	// LimitPrice is the worst acceptable price: a ceiling for buys and a floor for sells.
+	LimitPrice float64
+	// MaxSpreadBps caps how far the quoted price may sit on the wrong side of ReferencePrice.
+	MaxSpreadBps   float64
+	ReferencePrice float64
+	// Accept is an optional custom check. Returning an error rejects the quote.
+	Accept func(quote QuoteResponse, side string, price float64) error
+	// SafetyMargin is subtracted from t_expiry; quotes are never executed after that point.
+	// Defaults to 250ms.
+	SafetyMargin time.Duration
+	// StatusPollInterval and ConfirmWindow control how long an ambiguous execution is
+	// confirmed through GetQuoteStatus after t_expiry. Default to 250ms and 5s.
+	StatusPollInterval time.Duration
+	ConfirmWindow      time.Duration
+	// Clock supplies the server time t_expiry is compared with. QuoteAndExecute on a
+	// RestClient defaults it to the client's Config.Clock; nil reads the local clock.
+	Clock *Clock
+}
+
+// RFQResult is the outcome of a successful QuoteAndExecute. In dry-run mode it is
+// returned with ErrDryRun, holding the quote and the simulated execution.
+type RFQResult struct {
+	Quote     QuoteResponse
+	Execution QuoteResponse
+	Side      string
+	// QuotedPrice is the quote's price for Side, the one checked against the policy.
+	QuotedPrice float64
+	// Price is the effective price of the fill, taken from the execution response. It
+	// falls back to QuotedPrice when the response carries no price.
+	Price         float64
+	ExecutionTime time.Time
+	// Confirmed is true when the fill was established through GetQuoteStatus because
+	// the execute call itself failed ambiguously.
+	Confirmed bool
+}
+
+// QuoteAndExecute requests a quote, checks it against policy and executes it before expiry.
+func (client *RestClient) QuoteAndExecute(ctx context.Context, quoteParams QuoteRequest,
+	policy AcceptancePolicy) (RFQResult, error) {
+	if policy.Clock == nil {
+		policy.Clock = client.Config.Clock
+	}
+	return QuoteAndExecuteWith(ctx, client, quoteParams, policy)
+}
+
+// QuoteAndExecuteWith runs the QuoteAndExecute workflow against any Quoter.
+func QuoteAndExecuteWith(ctx context.Context, quoter Quoter, quoteParams QuoteRequest,
+	policy AcceptancePolicy) (RFQResult, error) {
+	var result RFQResult
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	side := policy.Side
+	if side == "" {
+		side = quoteParams.Side
+	}
+	if side != "buy" && side != "sell" {
+		return result, fmt.Errorf("cannot execute side %q; set AcceptancePolicy.Side for two_way quotes", side)
+	}
+	if quoteParams.Side != "two_way" && quoteParams.Side != side {
+		return result, fmt.Errorf("cannot execute side %s of a %s quote", side, quoteParams.Side)
+	}
+
+	quote, err := quoter.GetQuote(quoteParams)
+	if err != nil {
+		return result, err
+	}
+	result.Quote = quote
+	result.Side = side
+	price := quote.BuyPrice
+	if side == "sell" {
+		price = quote.SellPrice
+	}
+	if !price.Valid {
+		return result, fmt.Errorf("%w: quote %s has no %s price", ErrQuoteRejected, quote.FxQuoteId, side)
+	}
+	result.QuotedPrice = price.Float64
+
+	if err := policy.check(quote, side, result.QuotedPrice); err != nil {
+		return result, err
+	}
+
+	margin := policy.SafetyMargin
+	if margin <= 0 {
+		margin = defaultExpirySafetyMargin
+	}
+	if !policy.Clock.Now().Before(quote.ExpiryTime.Add(-margin)) {
+		return result, ErrQuoteExpired
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	execution, err := quoter.ExecuteQuote(QuoteExecutionRequest{FxQuoteId: quote.FxQuoteId, Side: side})
+	if errors.Is(err, ErrDryRun) {
+		result.Execution = execution
+		return result, err
+	}
+	if err == nil {
+		if !execution.IsFilled {
+			return result, ErrQuoteNotFilled
+		}
+		result.setExecution(execution)
+		return result, nil
+	}
+	if !isAmbiguous(err) {
+		return result, err
+	}
+
+	status, err := confirmExecution(ctx, quoter, quote, policy, err)
+	if err != nil {
+		return result, err
+	}
+	result.setExecution(status)
+	result.Confirmed = true
+	return result, nil
+}
+
+// setExecution records the filled execution and its effective price.
+func (result *RFQResult) setExecution(execution QuoteResponse) {
+	result.Execution = execution
+	result.ExecutionTime = execution.ExecutionTime.Time
+	price, ok := execution.PriceExecuted.Get()
+	if !ok {
+		price, ok = execution.BuyPrice.Get()
+		if result.Side == "sell" {
+			price, ok = execution.SellPrice.Get()
+		}
+	}
+	if !ok || price <= 0 {
+		price = result.QuotedPrice
+	}
+	result.Price = price
+}
+
+func (policy AcceptancePolicy) check(quote QuoteResponse, side string, price float64) error {
+	if price <= 0 {
+		return fmt.Errorf("%w: no %s price quoted", ErrQuoteRejected, side)
+	}
+
+	if policy.LimitPrice > 0 {
+		if side == "buy" && price > policy.LimitPrice {
+			return fmt.Errorf("%w: buy price %v above limit %v", ErrQuoteRejected, price, policy.LimitPrice)
+		}
+		if side == "sell" && price < policy.LimitPrice {
+			return fmt.Errorf("%w: sell price %v below limit %v", ErrQuoteRejected, price, policy.LimitPrice)
+		}
+	}
+
+	if policy.MaxSpreadBps > 0 && policy.ReferencePrice > 0 {
+		spreadBps := (price - policy.ReferencePrice) / policy.ReferencePrice * 10000
+		if side == "sell" {
+			spreadBps = -spreadBps
+		}
+		if spreadBps > policy.MaxSpreadBps {
+			return fmt.Errorf("%w: %.2f bps from reference exceeds %.2f bps", ErrQuoteRejected, spreadBps, policy.MaxSpreadBps)
+		}
+	}
+
+	if policy.Accept != nil {
+		if err := policy.Accept(quote, side, price); err != nil {
+			return fmt.Errorf("%w: %v", ErrQuoteRejected, err)
+		}
+	}
+	return nil
+}
+
+// LocalRejection is implemented by errors for calls refused before anything was sent to
+// FalconX, such as risk rejections. Such a call cannot have filled, so it is never
+// treated as ambiguous.
+type LocalRejection interface {
+	error
+	LocalRejection()
+}
+
+// localRejections are the errors this package returns without sending a request.
+var localRejections = []error{ErrKillSwitchTripped, ErrHedgeLoser, ErrDryRun}
+
+// isAmbiguous reports whether a failed execute call may still have filled: transport
+// errors and server-side failures are ambiguous, client errors and local rejections are
+// not.
+func isAmbiguous(err error) bool {
+	var apiErr Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= 500
+	}
+	var local LocalRejection
+	if errors.As(err, &local) {
+		return false
+	}
+	for _, rejection := range localRejections {
+		if errors.Is(err, rejection) {
+			return false
+		}
+	}
+	return true
+}
+
+// confirmExecution polls GetQuoteStatus until the quote shows as filled, or until it has
+// expired for longer than the confirm window.
+func confirmExecution(ctx context.Context, quoter Quoter, quote QuoteResponse,
+	policy AcceptancePolicy, executeErr error) (QuoteResponse, error) {
+	interval := policy.StatusPollInterval
+	if interval <= 0 {
+		interval = defaultStatusPollInterval
+	}
+	window := policy.ConfirmWindow
+	if window <= 0 {
+		window = defaultConfirmWindow
+	}
+	giveUp := quote.ExpiryTime.Add(window)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := quoter.GetQuoteStatus(quote.FxQuoteId)
+		if err == nil {
+			if status.IsFilled {
+				return status, nil
+			}
+			if policy.Clock.Now().After(quote.ExpiryTime) {
+				return status, ErrQuoteNotFilled
+			}
+		}
+		if policy.Clock.Now().After(giveUp) {
+			return status, fmt.Errorf("%w: %v", ErrExecutionUnknown, executeErr)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("%w: %v", ErrExecutionUnknown, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
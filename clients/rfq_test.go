@@ -0,0 +1,174 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/clients/mocks"
+)
+
+func TestQuoteAndExecuteKillSwitchDoesNotPoll(t *testing.T) {
+	killSwitch, err := clients.NewKillSwitch("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	quoter := &mocks.QuoterMock{
+		GetQuoteFunc: func(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+			// The switch trips between the quote and its execution.
+			killSwitch.Trip("test")
+			return clients.QuoteResponse{
+				FxQuoteId:  "q1",
+				BuyPrice:   clients.NewNullFloat64(100),
+				ExpiryTime: time.Now().Add(time.Minute),
+			}, nil
+		},
+		ExecuteQuoteFunc: func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+			return clients.QuoteResponse{}, killSwitch.Check()
+		},
+		GetQuoteStatusFunc: func(fxQuoteID string) (clients.QuoteResponse, error) {
+			return clients.QuoteResponse{}, nil
+		},
+	}
+
+	start := time.Now()
+	_, err = clients.QuoteAndExecuteWith(context.Background(), quoter,
+		clients.QuoteRequest{Side: "buy"}, clients.AcceptancePolicy{})
+	if !errors.Is(err, clients.ErrKillSwitchTripped) {
+		t.Fatalf("err = %v, want ErrKillSwitchTripped", err)
+	}
+	if calls := len(quoter.GetQuoteStatusCalls()); calls != 0 {
+		t.Errorf("GetQuoteStatus called %d times, want 0", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("QuoteAndExecute took %v, want an immediate failure", elapsed)
+	}
+}
+
+type localRejection struct{}
+
+func (localRejection) Error() string   { return "refused locally" }
+func (localRejection) LocalRejection() {}
+
+func TestQuoteAndExecuteAmbiguity(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantPolls bool
+	}{
+		{"kill switch", clients.ErrKillSwitchTripped, false},
+		{"hedge loser", clients.ErrHedgeLoser, false},
+		{"local rejection", localRejection{}, false},
+		{"client error", clients.Error{Code: 400, Reason: "bad request"}, false},
+		{"server error", clients.Error{Code: 502, Reason: "bad gateway"}, true},
+		{"transport error", errors.New("connection reset"), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quoter := &mocks.QuoterMock{
+				GetQuoteFunc: func(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+					return clients.QuoteResponse{
+						FxQuoteId:  "q1",
+						BuyPrice:   clients.NewNullFloat64(100),
+						ExpiryTime: time.Now().Add(time.Minute),
+					}, nil
+				},
+				ExecuteQuoteFunc: func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+					return clients.QuoteResponse{}, test.err
+				},
+				GetQuoteStatusFunc: func(fxQuoteID string) (clients.QuoteResponse, error) {
+					return clients.QuoteResponse{FxQuoteId: fxQuoteID, IsFilled: true}, nil
+				},
+			}
+			result, err := clients.QuoteAndExecuteWith(context.Background(), quoter,
+				clients.QuoteRequest{Side: "buy"}, clients.AcceptancePolicy{})
+			polled := len(quoter.GetQuoteStatusCalls()) > 0
+			if polled != test.wantPolls {
+				t.Fatalf("polled = %v, want %v", polled, test.wantPolls)
+			}
+			if test.wantPolls && (err != nil || !result.Confirmed) {
+				t.Errorf("result = %+v, %v; want a confirmed fill", result, err)
+			}
+			if !test.wantPolls && err == nil {
+				t.Error("err = nil, want the execute error")
+			}
+		})
+	}
+}
+
+func TestQuoteAndExecuteEffectivePrice(t *testing.T) {
+	tests := []struct {
+		name      string
+		execution clients.QuoteResponse
+		want      float64
+	}{
+		{"price executed", clients.QuoteResponse{IsFilled: true, SellPrice: clients.NewNullFloat64(99.5),
+			PriceExecuted: clients.NewNullFloat64(99.4)}, 99.4},
+		{"side price", clients.QuoteResponse{IsFilled: true, BuyPrice: clients.NewNullFloat64(101),
+			SellPrice: clients.NewNullFloat64(99.5)}, 99.5},
+		{"no price", clients.QuoteResponse{IsFilled: true}, 99},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quoter := &mocks.QuoterMock{
+				GetQuoteFunc: func(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+					return clients.QuoteResponse{
+						FxQuoteId:  "q1",
+						BuyPrice:   clients.NewNullFloat64(101),
+						SellPrice:  clients.NewNullFloat64(99),
+						ExpiryTime: time.Now().Add(time.Minute),
+					}, nil
+				},
+				ExecuteQuoteFunc: func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+					return test.execution, nil
+				},
+			}
+			result, err := clients.QuoteAndExecuteWith(context.Background(), quoter,
+				clients.QuoteRequest{Side: "two_way"}, clients.AcceptancePolicy{Side: "sell"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.QuotedPrice != 99 || result.Price != test.want {
+				t.Errorf("QuotedPrice, Price = %v, %v; want 99, %v", result.QuotedPrice, result.Price, test.want)
+			}
+		})
+	}
+}
+
+func TestQuoteAndExecuteExpiryUsesClock(t *testing.T) {
+	// The server clock is a minute ahead, so a quote the local clock thinks has 30s
+	// left has already expired.
+	clock := clients.NewClock(clients.ClockConfig{})
+	now := time.Now()
+	clock.Observe(now.Add(time.Minute).Format(http.TimeFormat), now, now)
+
+	quoter := &mocks.QuoterMock{
+		GetQuoteFunc: func(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+			return clients.QuoteResponse{
+				FxQuoteId:  "q1",
+				BuyPrice:   clients.NewNullFloat64(100),
+				ExpiryTime: time.Now().Add(30 * time.Second),
+			}, nil
+		},
+		ExecuteQuoteFunc: func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+			return clients.QuoteResponse{IsFilled: true}, nil
+		},
+	}
+	_, err := clients.QuoteAndExecuteWith(context.Background(), quoter,
+		clients.QuoteRequest{Side: "buy"}, clients.AcceptancePolicy{Clock: clock})
+	if !errors.Is(err, clients.ErrQuoteExpired) {
+		t.Fatalf("err = %v, want ErrQuoteExpired", err)
+	}
+	if calls := len(quoter.ExecuteQuoteCalls()); calls != 0 {
+		t.Errorf("ExecuteQuote called %d times, want 0", calls)
+	}
+
+	// Without the clock the same quote is executed.
+	if _, err := clients.QuoteAndExecuteWith(context.Background(), quoter,
+		clients.QuoteRequest{Side: "buy"}, clients.AcceptancePolicy{}); err != nil {
+		t.Fatal(err)
+	}
+}
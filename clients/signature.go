@@ -1,24 +1,20 @@
 package clients
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
+	"github.com/falconxio/falconx-go/auth"
 )
 
+// GenerateSig signs message with the base64 encoded secret. See auth.SignWithSecret.
 func GenerateSig(message, secret string) (string, error) {
-	key, err := base64.StdEncoding.DecodeString(secret)
+	signature, err := auth.SignWithSecret(message, secret)
 	if err != nil {
 		Error := Error{Code: 401, Reason: "Bad Secret Key"}
 		return "", error(Error)
 	}
+	return signature, nil
+}
 
-	signature := hmac.New(sha256.New, key)
-	_, err = signature.Write([]byte(message))
-	if err != nil {
-		Error := Error{Code: 401, Reason: "Bad API Key/Pass Phrase"}
-		return "", error(Error)
-	}
-
-	return base64.StdEncoding.EncodeToString(signature.Sum(nil)), nil
+// ComputeHmac256 signs message with a decoded key. See auth.Sign.
+func ComputeHmac256(message string, key []byte) string {
+	return auth.Sign(message, key)
 }
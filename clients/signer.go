@@ -0,0 +1,329 @@
+package clients
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/auth"
+)
+
+const (
+	defaultSignerTimeout = 2 * time.Second
+	maxSignerMessageSize = 1 << 20
+)
+
+// Signer computes the FX-ACCESS-SIGN value for a prehash message: the base64 encoded
+// HMAC-SHA256 of the message keyed with the decoded API secret. Set it on
+// RestClientConfig and SocketClientConfig to keep the secret out of the client.
+type Signer interface {
+	Sign(message string) (string, error)
+}
+
+// SecretSigner signs with a secret held in memory.
+type SecretSigner struct {
+	key []byte
+}
+
+// NewSecretSigner decodes a base64 secret.
+func NewSecretSigner(secret string) (*SecretSigner, error) {
+	key, err := auth.DecodeSecret(secret)
+	if err != nil {
+		return nil, Error{Code: 401, Reason: "Bad Secret Key"}
+	}
+	return &SecretSigner{key: key}, nil
+}
+
+func (signer *SecretSigner) Sign(message string) (string, error) {
+	return auth.Sign(message, signer.key), nil
+}
+
+// FileSigner signs with a secret read from a file that only its owner can read, such
+// as a mounted secret. The file is read again whenever it changes, so the secret can be
+// replaced without a restart.
+type FileSigner struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	signer  *SecretSigner
+}
+
+// NewFileSigner reads the base64 secret stored at path.
+func NewFileSigner(path string) (*FileSigner, error) {
+	signer := &FileSigner{path: path}
+	if _, err := signer.current(); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+func (signer *FileSigner) Sign(message string) (string, error) {
+	current, err := signer.current()
+	if err != nil {
+		return "", err
+	}
+	return current.Sign(message)
+}
+
+func (signer *FileSigner) current() (*SecretSigner, error) {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+
+	info, err := os.Stat(signer.path)
+	if err != nil {
+		return nil, fmt.Errorf("file signer: %w", err)
+	}
+	if signer.signer != nil && info.ModTime().Equal(signer.modTime) && info.Size() == signer.size {
+		return signer.signer, nil
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("file signer: %s is accessible by group or others (mode %v)", signer.path, info.Mode().Perm())
+	}
+
+	data, err := ioutil.ReadFile(signer.path)
+	if err != nil {
+		return nil, fmt.Errorf("file signer: %w", err)
+	}
+	secret, err := NewSecretSigner(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("file signer: %s: %w", signer.path, err)
+	}
+	signer.signer, signer.modTime, signer.size = secret, info.ModTime(), info.Size()
+	return secret, nil
+}
+
+// ErrSignerServerClosed is returned by Serve after Close.
+var ErrSignerServerClosed = errors.New("signer server closed")
+
+// signRequest and signResponse are the newline delimited JSON messages exchanged with a
+// signing daemon.
+type signRequest struct {
+	Message string `json:"message"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// DaemonSigner asks a signing daemon listening on a Unix socket to sign, so the secret
+// never enters the trading process. Run the daemon with SignerServer.
+type DaemonSigner struct {
+	path    string
+	timeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewDaemonSigner creates a signer for the daemon at socketPath. Each Sign call must
+// complete within timeout, which defaults to two seconds. The connection is opened
+// lazily and reopened after any error.
+func NewDaemonSigner(socketPath string, timeout time.Duration) *DaemonSigner {
+	if timeout <= 0 {
+		timeout = defaultSignerTimeout
+	}
+	return &DaemonSigner{path: socketPath, timeout: timeout}
+}
+
+func (signer *DaemonSigner) Sign(message string) (string, error) {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+
+	signature, err := signer.roundTrip(message)
+	if err != nil {
+		signer.closeLocked()
+		return "", fmt.Errorf("signing daemon %s: %w", signer.path, err)
+	}
+	return signature, nil
+}
+
+func (signer *DaemonSigner) roundTrip(message string) (string, error) {
+	if signer.conn == nil {
+		conn, err := net.DialTimeout("unix", signer.path, signer.timeout)
+		if err != nil {
+			return "", err
+		}
+		signer.conn, signer.reader = conn, bufio.NewReader(conn)
+	}
+	if err := signer.conn.SetDeadline(time.Now().Add(signer.timeout)); err != nil {
+		return "", err
+	}
+
+	request, err := json.Marshal(signRequest{Message: message})
+	if err != nil {
+		return "", err
+	}
+	if _, err := signer.conn.Write(append(request, '\n')); err != nil {
+		return "", err
+	}
+
+	line, err := signer.reader.ReadBytes('\n')
+	if err != nil {
+		return "", err
+	}
+	var response signResponse
+	if err := json.Unmarshal(line, &response); err != nil {
+		return "", err
+	}
+	if response.Error != "" {
+		return "", errors.New(response.Error)
+	}
+	return response.Signature, nil
+}
+
+// Close closes the connection to the daemon.
+func (signer *DaemonSigner) Close() error {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+	return signer.closeLocked()
+}
+
+func (signer *DaemonSigner) closeLocked() error {
+	if signer.conn == nil {
+		return nil
+	}
+	err := signer.conn.Close()
+	signer.conn, signer.reader = nil, nil
+	return err
+}
+
+// SignerServer is the signing daemon side of DaemonSigner. It signs every message it
+// receives with its Signer.
+type SignerServer struct {
+	signer Signer
+
+	mu         sync.Mutex
+	listener   net.Listener
+	socketPath string
+	closed     bool
+}
+
+// NewSignerServer creates a server signing with signer.
+func NewSignerServer(signer Signer) *SignerServer {
+	return &SignerServer{signer: signer}
+}
+
+// ListenAndServe listens on a Unix socket at socketPath, readable and writable by the
+// owner only, and serves until Close is called. A stale socket file is removed first.
+// The socket is bound inside a private directory and only moved to socketPath once its
+// permissions are restricted, so no other user can connect in between.
+func (server *SignerServer) ListenAndServe(socketPath string) error {
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	listener, err := listenPrivate(socketPath)
+	if err != nil {
+		return err
+	}
+	server.mu.Lock()
+	server.socketPath = socketPath
+	server.mu.Unlock()
+	return server.Serve(listener)
+}
+
+// listenPrivate binds a Unix socket in a new 0700 directory next to socketPath,
+// restricts it to the owner and renames it to socketPath.
+func listenPrivate(socketPath string) (net.Listener, error) {
+	dir, err := ioutil.TempDir(filepath.Dir(socketPath), ".falconx-signer-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	private := filepath.Join(dir, "signer.sock")
+	listener, err := net.Listen("unix", private)
+	if err != nil {
+		return nil, err
+	}
+	// The socket file is removed by Close, not by the listener, since it was renamed.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(private, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(private, socketPath); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// Serve accepts connections on listener until Close is called.
+func (server *SignerServer) Serve(listener net.Listener) error {
+	server.mu.Lock()
+	if server.closed {
+		server.mu.Unlock()
+		listener.Close()
+		return ErrSignerServerClosed
+	}
+	server.listener = listener
+	server.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			server.mu.Lock()
+			closed := server.closed
+			server.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go server.serveConn(conn)
+	}
+}
+
+// Close stops accepting connections.
+func (server *SignerServer) Close() error {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.closed = true
+	if server.listener == nil {
+		return nil
+	}
+	err := server.listener.Close()
+	if server.socketPath != "" {
+		os.Remove(server.socketPath)
+	}
+	return err
+}
+
+func (server *SignerServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxSignerMessageSize)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var request signRequest
+		var response signResponse
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			response.Error = "malformed request"
+		} else if signature, err := server.signer.Sign(request.Message); err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Signature = signature
+		}
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Signing daemon connection error: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package clients_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// serveSigner runs a SignerServer for signer on a socket in a temporary directory and
+// returns the socket path once it accepts connections. The server is closed, and its
+// ListenAndServe result checked, when the test ends.
+func serveSigner(t *testing.T, signer clients.Signer) string {
+	t.Helper()
+	path := filepath.Join(tempDir(t), "signer.sock")
+	server := clients.NewSignerServer(signer)
+	served := make(chan error, 1)
+	go func() { served <- server.ListenAndServe(path) }()
+	waitFor(t, "the signer socket", func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	})
+	t.Cleanup(func() {
+		server.Close()
+		if err := <-served; err != nil {
+			t.Errorf("ListenAndServe = %v, want nil after Close", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("socket still present after Close: %v", err)
+		}
+	})
+	return path
+}
+
+func TestSignerServerRoundTrip(t *testing.T) {
+	secret, err := clients.NewSecretSigner("c2VjcmV0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := serveSigner(t, secret)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %v, want 0600", perm)
+	}
+
+	daemon := clients.NewDaemonSigner(path, 0)
+	defer daemon.Close()
+	// The second message reuses the connection opened by the first.
+	for _, message := range []string{"1561645200GET/v1/balances", "1561645201POST/v1/quotes{}"} {
+		want, _ := secret.Sign(message)
+		got, err := daemon.Sign(message)
+		if err != nil || got != want {
+			t.Errorf("Sign(%q) = %q, %v; want %q", message, got, err, want)
+		}
+	}
+}
+
+type signerFunc func(message string) (string, error)
+
+func (f signerFunc) Sign(message string) (string, error) { return f(message) }
+
+func TestSignerServerErrorReachesCaller(t *testing.T) {
+	var calls int32
+	path := serveSigner(t, signerFunc(func(message string) (string, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return "", errors.New("key is locked")
+		}
+		return "signature", nil
+	}))
+
+	daemon := clients.NewDaemonSigner(path, 0)
+	defer daemon.Close()
+	if _, err := daemon.Sign("message"); err == nil || !strings.Contains(err.Error(), "key is locked") {
+		t.Fatalf("Sign error = %v, want the server's signing error", err)
+	}
+	// The connection is reopened after the error.
+	if signature, err := daemon.Sign("message"); err != nil || signature != "signature" {
+		t.Errorf("Sign after an error = %q, %v; want signature", signature, err)
+	}
+}
+
+func TestDaemonSignerWithoutServer(t *testing.T) {
+	daemon := clients.NewDaemonSigner(filepath.Join(tempDir(t), "missing.sock"), 0)
+	if _, err := daemon.Sign("message"); err == nil {
+		t.Error("Sign succeeded without a signing daemon")
+	}
+}
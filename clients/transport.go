@@ -0,0 +1,213 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestTimeout      = 60 * time.Second
+	defaultMaxIdleConnsPerHost = 32
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultDialTimeout         = 5 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultTLSHandshakeTimeout = 5 * time.Second
+)
+
+// TransportConfig tunes the HTTP transport NewRestClient builds. The defaults keep a
+// pool of warm connections large enough for concurrent quoting, so TLS handshakes stay
+// off the hot path.
+type TransportConfig struct {
+	// Timeout bounds a whole request, including reading the body. Defaults to 60s.
+	Timeout time.Duration
+	// DialTimeout bounds opening a TCP connection. Defaults to 5s.
+	DialTimeout time.Duration
+	// KeepAlive is the TCP keep-alive period. Defaults to 30s.
+	KeepAlive time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake. Defaults to 5s.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout bounds the wait for response headers once the request is
+	// written. It applies to every endpoint, including slow history queries, so it is
+	// unset by default and only Timeout applies.
+	ResponseHeaderTimeout time.Duration
+	// MaxIdleConnsPerHost is the number of idle connections kept open. Defaults to 32.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps open connections; 0 means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for longer. Defaults to 90s.
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 keeps requests on HTTP/1.1. By default HTTP/2 is negotiated when the
+	// server supports it, multiplexing requests over one connection.
+	DisableHTTP2 bool
+	// OnTiming, when set, receives the timing breakdown of every request.
+	OnTiming func(RequestTiming)
+}
+
+// RequestTiming breaks down where the time of one request went. Phases skipped
+// because a pooled connection was reused are zero.
+type RequestTiming struct {
+	Method string
+	URL    string
+	// Reused reports whether the request ran on a pooled connection.
+	Reused bool
+	// DNS, Connect and TLS are the name lookup, TCP connect and TLS handshake.
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// Wait is from the request being written to the first response byte.
+	Wait time.Duration
+	// Total is from sending the request to receiving the response headers.
+	Total time.Duration
+	// StatusCode is 0 when Err is set.
+	StatusCode int
+	Err        error
+}
+
+func newHTTPClient(config TransportConfig) *http.Client {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultRequestTimeout
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = defaultDialTimeout
+	}
+	if config.KeepAlive <= 0 {
+		config.KeepAlive = defaultKeepAlive
+	}
+	if config.TLSHandshakeTimeout <= 0 {
+		config.TLSHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+	if config.MaxIdleConnsPerHost <= 0 {
+		config.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeout <= 0 {
+		config.IdleConnTimeout = defaultIdleConnTimeout
+	}
+
+	dialer := &net.Dialer{Timeout: config.DialTimeout, KeepAlive: config.KeepAlive}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     !config.DisableHTTP2,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		MaxIdleConns:          config.MaxIdleConnsPerHost,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	if config.DisableHTTP2 {
+		// A non-nil empty map keeps the transport from upgrading to HTTP/2.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return &http.Client{Timeout: config.Timeout, Transport: transport}
+}
+
+// requestTrace collects the httptrace events of one request.
+type requestTrace struct {
+	mu                               sync.Mutex
+	start                            time.Time
+	dnsStart, connectStart, tlsStart time.Time
+	wrote                            time.Time
+	timing                           RequestTiming
+}
+
+func traceRequest(req *http.Request) (*http.Request, *requestTrace) {
+	trace := &requestTrace{start: time.Now(), timing: RequestTiming{Method: req.Method, URL: req.URL.String()}}
+	clientTrace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			trace.mark(func() { trace.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			trace.mark(func() { trace.timing.DNS = time.Since(trace.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			trace.mark(func() { trace.connectStart = time.Now() })
+		},
+		ConnectDone: func(string, string, error) {
+			trace.mark(func() { trace.timing.Connect = time.Since(trace.connectStart) })
+		},
+		TLSHandshakeStart: func() {
+			trace.mark(func() { trace.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			trace.mark(func() { trace.timing.TLS = time.Since(trace.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			trace.mark(func() { trace.timing.Reused = info.Reused })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			trace.mark(func() { trace.wrote = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			trace.mark(func() {
+				if !trace.wrote.IsZero() {
+					trace.timing.Wait = time.Since(trace.wrote)
+				}
+			})
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), clientTrace)), trace
+}
+
+func (trace *requestTrace) mark(update func()) {
+	trace.mu.Lock()
+	update()
+	trace.mu.Unlock()
+}
+
+// finish completes the timing once the response headers, or an error, arrived.
+func (trace *requestTrace) finish(res *http.Response, err error) RequestTiming {
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	trace.timing.Total = time.Since(trace.start)
+	trace.timing.Err = err
+	if err == nil {
+		trace.timing.StatusCode = res.StatusCode
+	}
+	return trace.timing
+}
+
+// Warmup opens up to connections connections to FalconX in parallel, completing their
+// TLS handshakes, so the first quotes reuse warm connections. It also syncs the clock.
+// Call it at startup; connections defaults to 1.
+func (client *RestClient) Warmup(ctx context.Context, connections int) error {
+	if connections <= 0 {
+		connections = 1
+	}
+
+	errs := make(chan error, connections)
+	for i := 0; i < connections; i++ {
+		go func() {
+			errs <- client.warmupConn(ctx)
+		}()
+	}
+
+	var firstErr error
+	for i := 0; i < connections; i++ {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (client *RestClient) warmupConn(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", client.Config.BaseURL, nil)
+	if err != nil {
+		return err
+	}
+	sent := time.Now()
+	res, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	client.Config.Clock.Observe(res.Header.Get("Date"), sent, time.Now())
+	return nil
+}
@@ -0,0 +1,40 @@
+package clients
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransportHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	for _, test := range []struct {
+		disable bool
+		want    string
+	}{
+		{false, "HTTP/2.0"},
+		{true, "HTTP/1.1"},
+	} {
+		client := newHTTPClient(TransportConfig{DisableHTTP2: test.disable})
+		transport := client.Transport.(*http.Transport)
+		transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+		if transport.ResponseHeaderTimeout != 0 {
+			t.Errorf("ResponseHeaderTimeout = %v, want unset", transport.ResponseHeaderTimeout)
+		}
+
+		res, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.Proto != test.want {
+			t.Errorf("DisableHTTP2 %v: protocol %s, want %s", test.disable, res.Proto, test.want)
+		}
+	}
+}
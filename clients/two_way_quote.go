@@ -0,0 +1,83 @@
+package clients
+
+import (
+	"fmt"
+	"time"
+)
+
+// TwoWayQuote is a two_way quote together with its spread analytics.
+type TwoWayQuote struct {
+	QuoteResponse
+	// Mid is the average of BuyPrice and SellPrice.
+	Mid float64
+	// Spread is BuyPrice minus SellPrice, in quote token.
+	Spread float64
+	// SpreadBps is Spread relative to Mid, in basis points.
+	SpreadBps float64
+}
+
+// NewTwoWayQuote computes the analytics for a quote that carries both prices.
+func NewTwoWayQuote(quote QuoteResponse) (TwoWayQuote, error) {
+	buy, sell := quote.BuyPrice.Or(0), quote.SellPrice.Or(0)
+	if buy <= 0 || sell <= 0 {
+		return TwoWayQuote{}, fmt.Errorf("quote %s is not two way: buy_price %v, sell_price %v",
+			quote.FxQuoteId, quote.BuyPrice, quote.SellPrice)
+	}
+
+	mid := (buy + sell) / 2
+	spread := buy - sell
+	return TwoWayQuote{
+		QuoteResponse: quote,
+		Mid:           mid,
+		Spread:        spread,
+		SpreadBps:     spread / mid * 10000,
+	}, nil
+}
+
+// TimeToExpiry returns how long is left until t_expiry. It is negative once the quote expired.
+func (quote TwoWayQuote) TimeToExpiry() time.Duration {
+	return time.Until(quote.ExpiryTime)
+}
+
+// Price returns the price for side, either buy or sell.
+func (quote TwoWayQuote) Price(side string) (float64, error) {
+	switch side {
+	case "buy":
+		return quote.BuyPrice.Float64, nil
+	case "sell":
+		return quote.SellPrice.Float64, nil
+	}
+	return 0, fmt.Errorf("invalid side %q, must be buy or sell", side)
+}
+
+// GetTwoWayQuote requests a two_way quote and returns it with mid and spread analytics.
+func (client *RestClient) GetTwoWayQuote(tokenPair TokenPair, quantity Quantity, clientOrderId string) (TwoWayQuote, error) {
+	return GetTwoWayQuoteWith(client, tokenPair, quantity, clientOrderId)
+}
+
+// GetTwoWayQuoteWith runs GetTwoWayQuote against any Quoter.
+func GetTwoWayQuoteWith(quoter Quoter, tokenPair TokenPair, quantity Quantity, clientOrderId string) (TwoWayQuote, error) {
+	quote, err := quoter.GetQuote(QuoteRequest{
+		TokenPair:     tokenPair,
+		Quantity:      quantity,
+		Side:          "two_way",
+		ClientOrderId: clientOrderId,
+	})
+	if err != nil {
+		return TwoWayQuote{QuoteResponse: quote}, err
+	}
+	return NewTwoWayQuote(quote)
+}
+
+// ExecuteTwoWayQuote executes one side, buy or sell, of a quote from GetTwoWayQuote.
+func (client *RestClient) ExecuteTwoWayQuote(quote TwoWayQuote, side string) (QuoteResponse, error) {
+	return ExecuteTwoWayQuoteWith(client, quote, side)
+}
+
+// ExecuteTwoWayQuoteWith runs ExecuteTwoWayQuote against any Quoter.
+func ExecuteTwoWayQuoteWith(quoter Quoter, quote TwoWayQuote, side string) (QuoteResponse, error) {
+	if _, err := quote.Price(side); err != nil {
+		return QuoteResponse{}, err
+	}
+	return quoter.ExecuteQuote(QuoteExecutionRequest{FxQuoteId: quote.FxQuoteId, Side: side})
+}
@@ -1,16 +1,16 @@
 package clients
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 
+	"github.com/falconxio/falconx-go/auth"
 	gosocketio "github.com/graarh/golang-socketio"
 	"github.com/graarh/golang-socketio/transport"
 
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -18,13 +18,27 @@ const (
 	pingInterval            = 20 * time.Second
 	webSocketSecureProtocol = "wss://"
 	socketioUrl             = "/socket.io/?EIO=3&transport=websocket"
+	onStream                = "stream"
+	emitSubscribe           = "subscribe"
+	emitUnsubscribe         = "unsubscribe"
 )
 
+// ErrNotConnected is returned by SocketClient calls made before Connect succeeded.
+var ErrNotConnected = errors.New("socket client not connected")
+
 type SocketClientConfig struct {
 	Host       string
 	Secret     string
 	APIKey     string
 	Passphrase string
+	// Signer, when set, signs the handshake instead of Secret, which can then be left empty.
+	Signer Signer
+	// Credentials, when set, supplies the credentials of every handshake instead of the
+	// fields above. A rotation takes effect on the next Connect or Reconnect.
+	Credentials CredentialsProvider
+	// Clock corrects the handshake timestamp; share RestClientConfig.Clock so the
+	// offset measured by REST requests applies here too. Nil uses the local clock.
+	Clock *Clock
 }
 
 type SocketClient struct {
@@ -32,12 +46,10 @@ type SocketClient struct {
 	Namespace  string
 	Transport  *transport.WebsocketTransport
 	Connection *gosocketio.Client
-}
 
-func ComputeHmac256(message string, key []byte) string {
-	h := hmac.New(sha256.New, key)
-	h.Write([]byte(message))
-	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	mu            sync.Mutex
+	subscriptions map[string]SubscriptionRequest
+	ladderHandler func(PriceLadder)
 }
 
 func NewSocketClient(config SocketClientConfig, namespace string) *SocketClient {
@@ -46,41 +58,183 @@ func NewSocketClient(config SocketClientConfig, namespace string) *SocketClient
 	wst.RequestHeader = make(http.Header)
 
 	client := SocketClient{
-		Config:     config,
-		Namespace:  namespace,
-		Transport:  wst,
-		Connection: nil,
+		Config:        config,
+		Namespace:     namespace,
+		Transport:     wst,
+		Connection:    nil,
+		subscriptions: make(map[string]SubscriptionRequest),
 	}
 
 	return &client
 }
 
 func (client *SocketClient) Connect() error {
-	err := client.AddAuth()
+	if err := client.AddAuth(); err != nil {
+		return fmt.Errorf("socket client: signing handshake: %w", err)
+	}
+	falconxWsUrl := webSocketSecureProtocol + client.Config.Host + socketioUrl
+	connection, err := gosocketio.Dial(falconxWsUrl, client.Transport)
 	if err != nil {
-		log.Fatal("Error creating authentication parameters.")
 		return err
 	}
-	falconxWsUrl := webSocketSecureProtocol + client.Config.Host + socketioUrl
-	client.Connection, err = gosocketio.Dial(falconxWsUrl, client.Transport)
-	return err
+	client.mu.Lock()
+	client.Connection = connection
+	client.mu.Unlock()
+	return nil
 }
 
+// AddAuth signs the handshake headers with the current credentials, replacing those of
+// any previous connection.
 func (client *SocketClient) AddAuth() error {
-	timestamp := fmt.Sprintf("%d", time.Now().Unix())
-	method := "GET"
-	path := "/socket.io/"
-	message := timestamp + method + path
-	hmac_key, err := base64.StdEncoding.DecodeString(client.Config.Secret)
+	credentials, err := client.credentials()
+	if err != nil {
+		return err
+	}
+	timestamp := auth.Timestamp(client.Config.Clock.Now())
+	signature, err := credentials.Sign(auth.SocketPrehash(timestamp))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range auth.Headers(credentials.APIKey, credentials.Passphrase, timestamp, signature) {
+		client.Transport.RequestHeader.Set(k, v)
+	}
+	return nil
+}
+
+func (client *SocketClient) credentials() (Credentials, error) {
+	if client.Config.Credentials != nil {
+		return client.Config.Credentials.Credentials()
+	}
+	return Credentials{
+		APIKey:     client.Config.APIKey,
+		Secret:     client.Config.Secret,
+		Passphrase: client.Config.Passphrase,
+		Signer:     client.Config.Signer,
+	}, nil
+}
+
+// Reconnect connects again, authenticating with the current credentials, then joins
+// Namespace and restores the OnPriceLadder handler and every subscription. Call it
+// after rotating credentials or when the connection drops. If the handshake cannot be
+// signed or the new connection cannot be opened, the existing connection is left open.
+func (client *SocketClient) Reconnect() error {
+	if err := client.AddAuth(); err != nil {
+		return fmt.Errorf("socket client: signing handshake: %w", err)
+	}
+	falconxWsUrl := webSocketSecureProtocol + client.Config.Host + socketioUrl
+	connection, err := gosocketio.Dial(falconxWsUrl, client.Transport)
 	if err != nil {
 		return err
 	}
-	signature := ComputeHmac256(message, hmac_key)
+	connection.ConnectNamespace(client.Namespace)
+
+	client.mu.Lock()
+	previous := client.Connection
+	client.Connection = connection
+	handler := client.ladderHandler
+	requests := make([]SubscriptionRequest, 0, len(client.subscriptions))
+	for _, request := range client.subscriptions {
+		requests = append(requests, request)
+	}
+	client.mu.Unlock()
+	if previous != nil {
+		previous.Close()
+	}
+
+	if handler != nil {
+		if err := client.OnPriceLadder(handler); err != nil {
+			return err
+		}
+	}
+	for _, request := range requests {
+		if err := client.Subscribe(request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnPriceLadder registers handler for the price ladders streamed for subscribed pairs.
+// It must be called after Connect.
+func (client *SocketClient) OnPriceLadder(handler func(PriceLadder)) error {
+	client.mu.Lock()
+	client.ladderHandler = handler
+	connection := client.Connection
+	client.mu.Unlock()
+	if connection == nil {
+		return ErrNotConnected
+	}
+	return connection.On(onStream, func(channel *gosocketio.Channel, ladder PriceLadder) {
+		handler(ladder)
+	})
+}
 
-	client.Transport.RequestHeader.Add("FX-ACCESS-SIGN", signature)
-	client.Transport.RequestHeader.Add("FX-ACCESS-TIMESTAMP", timestamp)
-	client.Transport.RequestHeader.Add("FX-ACCESS-KEY", client.Config.APIKey)
-	client.Transport.RequestHeader.Add("FX-ACCESS-PASSPHRASE", client.Config.Passphrase)
-	client.Transport.RequestHeader.Add("Content-Type", "application/json")
+// Subscribe starts streaming prices for a token pair and remembers the subscription,
+// keyed by ClientRequestID, so it can be cancelled later.
+func (client *SocketClient) Subscribe(request SubscriptionRequest) error {
+	connection, err := client.connection()
+	if err != nil {
+		return err
+	}
+	if err := connection.Emit(emitSubscribe, client.Namespace, &request); err != nil {
+		return err
+	}
+	client.mu.Lock()
+	client.subscriptions[request.ClientRequestID] = request
+	client.mu.Unlock()
 	return nil
 }
+
+// Unsubscribe stops the subscription made with clientRequestID.
+func (client *SocketClient) Unsubscribe(clientRequestID string) error {
+	client.mu.Lock()
+	request, ok := client.subscriptions[clientRequestID]
+	delete(client.subscriptions, clientRequestID)
+	connection := client.Connection
+	client.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if connection == nil {
+		return ErrNotConnected
+	}
+	return connection.Emit(emitUnsubscribe, client.Namespace, &request)
+}
+
+// connection returns the current connection, or ErrNotConnected before Connect.
+func (client *SocketClient) connection() (*gosocketio.Client, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.Connection == nil {
+		return nil, ErrNotConnected
+	}
+	return client.Connection, nil
+}
+
+// UnsubscribeAll stops every subscription made through Subscribe.
+func (client *SocketClient) UnsubscribeAll() error {
+	client.mu.Lock()
+	ids := make([]string, 0, len(client.subscriptions))
+	for id := range client.subscriptions {
+		ids = append(ids, id)
+	}
+	client.mu.Unlock()
+
+	var firstErr error
+	for _, id := range ids {
+		if err := client.Unsubscribe(id); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// UnsubscribeOnTrip stops every stream when killSwitch trips.
+func (client *SocketClient) UnsubscribeOnTrip(killSwitch *KillSwitch) {
+	killSwitch.OnTrip(func(reason string) {
+		if err := client.UnsubscribeAll(); err != nil {
+			log.Printf("Error unsubscribing after kill switch (%s): %v", reason, err)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+// Command falconx-signer is a local signing daemon for the FalconX API. It holds the API
+// secret and signs requests for clients configured with clients.DaemonSigner, so the
+// secret never enters the trading process.
+//
+//	falconx-signer -socket /run/falconx/signer.sock -secret-file /etc/falconx/secret
+//
+// The secret comes from -secret-file or, when that is not given, FALCONX_SECRET.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/config"
+)
+
+func main() {
+	socketPath := flag.String("socket", "", "Unix socket to listen on")
+	secretFile := flag.String("secret-file", "", "file holding the base64 API secret (default $FALCONX_SECRET)")
+	flag.Parse()
+
+	if *socketPath == "" {
+		log.Fatal("falconx-signer: -socket is required")
+	}
+
+	var signer clients.Signer
+	var err error
+	if *secretFile != "" {
+		signer, err = clients.NewFileSigner(*secretFile)
+	} else {
+		secret := os.Getenv(config.EnvSecret)
+		if secret == "" {
+			log.Fatalf("falconx-signer: -secret-file or %s is required", config.EnvSecret)
+		}
+		os.Unsetenv(config.EnvSecret)
+		signer, err = clients.NewSecretSigner(secret)
+	}
+	if err != nil {
+		log.Fatalf("falconx-signer: %v", err)
+	}
+
+	server := clients.NewSignerServer(signer)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.Close()
+	}()
+
+	log.Printf("Signing on %s", *socketPath)
+	if err := server.ListenAndServe(*socketPath); err != nil {
+		log.Fatalf("falconx-signer: %v", err)
+	}
+	os.Remove(*socketPath)
+}
@@ -0,0 +1,522 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+const streamingNamespace = "/streaming"
+
+func pairsCommand(fs *flag.FlagSet) func(env *environment) error {
+	return func(env *environment) error {
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		pairs, err := client.GetTradingPairs()
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(pairs))
+		for i, pair := range pairs {
+			rows[i] = []string{pair.BaseToken, pair.QuoteToken}
+		}
+		return env.printer.print(pairs, []string{"base_token", "quote_token"}, rows)
+	}
+}
+
+func quoteCommand(fs *flag.FlagSet) func(env *environment) error {
+	pair := fs.String("pair", "", "token pair, e.g. BTC/USD (required)")
+	quantity := fs.Float64("quantity", 0, "quantity (required)")
+	token := fs.String("token", "", "token the quantity is in (default base token)")
+	side := fs.String("side", "two_way", "buy, sell or two_way")
+	clientOrderID := fs.String("client-order-id", "", "client order ID (default generated)")
+
+	return func(env *environment) error {
+		tokenPair, err := parsePair(*pair)
+		if err != nil {
+			return err
+		}
+		if *quantity <= 0 {
+			return errors.New("-quantity must be positive")
+		}
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		quote, err := client.GetQuote(clients.QuoteRequest{
+			TokenPair:     tokenPair,
+			Quantity:      clients.Quantity{Token: quantityToken(*token, tokenPair), Value: *quantity},
+			Side:          *side,
+			ClientOrderId: orGenerated(*clientOrderID),
+		})
+		if err != nil {
+			return err
+		}
+		return printQuote(env, quote)
+	}
+}
+
+func executeCommand(fs *flag.FlagSet) func(env *environment) error {
+	id := fs.String("id", "", "fx_quote_id to execute (required)")
+	side := fs.String("side", "", "buy or sell (required)")
+
+	return func(env *environment) error {
+		if *id == "" || (*side != "buy" && *side != "sell") {
+			return errors.New("-id and -side buy|sell are required")
+		}
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		status, err := client.GetQuoteStatus(*id)
+		if err != nil {
+			return err
+		}
+		if status.IsFilled {
+			return fmt.Errorf("quote %s is already filled", *id)
+		}
+		price := status.BuyPrice
+		if *side == "sell" {
+			price = status.SellPrice
+		}
+		if !price.Valid {
+			return fmt.Errorf("quote %s has no %s price", *id, *side)
+		}
+		pair := status.TokenPair
+		action := fmt.Sprintf("Execute quote %s: %s %s %s on %s/%s at %s", *id, *side,
+			formatFloat(status.Quantity.Value), status.Quantity.Token, pair.BaseToken, pair.QuoteToken,
+			formatFloat(price.Float64))
+		if err := env.confirm(action); err != nil {
+			return err
+		}
+		quote, err := client.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: *id, Side: *side})
+		if err != nil && !errors.Is(err, clients.ErrDryRun) {
+			return err
+		}
+		return printQuote(env, quote)
+	}
+}
+
+func orderCommand(fs *flag.FlagSet) func(env *environment) error {
+	pair := fs.String("pair", "", "token pair, e.g. BTC/USD (required)")
+	quantity := fs.Float64("quantity", 0, "quantity (required)")
+	token := fs.String("token", "", "token the quantity is in (default base token)")
+	side := fs.String("side", "", "buy or sell (required)")
+	orderType := fs.String("type", "market", "market or limit")
+	limitPrice := fs.Float64("limit-price", 0, "limit price, for limit orders")
+	slippageBps := fs.Float64("slippage-bps", 0, "allowed slippage, for limit orders")
+	timeInForce := fs.String("tif", "fok", "time in force, for limit orders")
+	clientOrderID := fs.String("client-order-id", "", "client order ID (default generated)")
+
+	return func(env *environment) error {
+		tokenPair, err := parsePair(*pair)
+		if err != nil {
+			return err
+		}
+		if *quantity <= 0 || (*side != "buy" && *side != "sell") {
+			return errors.New("-quantity and -side buy|sell are required")
+		}
+		request := clients.OrderRequest{
+			TokenPair:     tokenPair,
+			Quantity:      clients.Quantity{Token: quantityToken(*token, tokenPair), Value: *quantity},
+			Side:          *side,
+			OrderType:     *orderType,
+			ClientOrderId: orGenerated(*clientOrderID),
+		}
+		switch *orderType {
+		case "market":
+		case "limit":
+			if *limitPrice <= 0 {
+				return errors.New("limit orders require -limit-price")
+			}
+			request.LimitPrice = *limitPrice
+			request.SlippageBps = *slippageBps
+			request.TimeInForce = *timeInForce
+		default:
+			return fmt.Errorf("invalid order type %q", *orderType)
+		}
+
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		action := fmt.Sprintf("Place %s %s order for %s %s on %s/%s", *side, *orderType,
+			formatFloat(*quantity), request.Quantity.Token, tokenPair.BaseToken, tokenPair.QuoteToken)
+		if request.LimitPrice > 0 {
+			action += " at " + formatFloat(request.LimitPrice)
+		}
+		if err := env.confirm(action); err != nil {
+			return err
+		}
+
+		order, err := client.PlaceOrder(request)
+		if err != nil && !errors.Is(err, clients.ErrDryRun) {
+			return err
+		}
+		return env.printer.printRecord(order, [][2]string{
+			{"fx_quote_id", order.FxQuoteId},
+			{"client_order_id", order.ClientOrderId},
+			{"status", order.Status},
+			{"token_pair", order.TokenPair.BaseToken + "/" + order.TokenPair.QuoteToken},
+			{"quantity", formatFloat(order.Quantity.Value) + " " + order.Quantity.Token},
+			{"side_executed", order.SideExecuted.Side},
+			{"is_filled", strconv.FormatBool(order.IsFilled)},
+			{"price_executed", formatFloat(order.ExecutedPrice())},
+			{"fee_usd", formatFloat(order.FeeUSD)},
+			{"t_execute", formatTime(order.ExecutionTime.Time)},
+		})
+	}
+}
+
+func quoteStatusCommand(fs *flag.FlagSet) func(env *environment) error {
+	id := fs.String("id", "", "fx_quote_id (required)")
+
+	return func(env *environment) error {
+		if *id == "" {
+			return errors.New("-id is required")
+		}
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		quote, err := client.GetQuoteStatus(*id)
+		if err != nil {
+			return err
+		}
+		return printQuote(env, quote)
+	}
+}
+
+func balancesCommand(fs *flag.FlagSet) func(env *environment) error {
+	return func(env *environment) error {
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		balances, err := client.GetBalances()
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(balances))
+		for i, balance := range balances {
+			rows[i] = []string{balance.Token, balance.Platform, formatFloat(balance.Balance)}
+		}
+		return env.printer.print(balances, []string{"token", "platform", "balance"}, rows)
+	}
+}
+
+func totalBalancesCommand(fs *flag.FlagSet) func(env *environment) error {
+	return func(env *environment) error {
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		balances, err := client.GetTotalBalances()
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(balances))
+		for i, balance := range balances {
+			rows[i] = []string{balance.Token, formatFloat(balance.TotalBalance)}
+		}
+		return env.printer.print(balances, []string{"token", "total_balance"}, rows)
+	}
+}
+
+func transfersCommand(fs *flag.FlagSet) func(env *environment) error {
+	start, end := rangeFlags(fs)
+
+	return func(env *environment) error {
+		from, to, err := parseRange(*start, *end)
+		if err != nil {
+			return err
+		}
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+
+		var transfers []clients.Transfer
+		iter := client.TransfersIter(context.Background(), from, to)
+		for iter.Next() {
+			transfers = append(transfers, iter.Transfer())
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(transfers))
+		for i, transfer := range transfers {
+			rows[i] = []string{formatTime(transfer.CreateTime), transfer.Type, transfer.Token,
+				formatFloat(transfer.Quantity), transfer.Platform, transfer.Status}
+		}
+		return env.printer.print(transfers, []string{"t_create", "type", "token", "quantity", "platform", "status"}, rows)
+	}
+}
+
+func tradesCommand(fs *flag.FlagSet) func(env *environment) error {
+	start, end := rangeFlags(fs)
+
+	return func(env *environment) error {
+		from, to, err := parseRange(*start, *end)
+		if err != nil {
+			return err
+		}
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+
+		var quotes []clients.QuoteResponse
+		iter := client.ExecutedQuotesIter(context.Background(), from, to)
+		for iter.Next() {
+			quotes = append(quotes, iter.Quote())
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(quotes))
+		for i, quote := range quotes {
+			rows[i] = []string{formatTime(quote.ExecutionTime.Time), quote.FxQuoteId,
+				quote.TokenPair.BaseToken + "/" + quote.TokenPair.QuoteToken, quote.SideExecuted.Side,
+				formatFloat(quote.Quantity.Value), quote.Quantity.Token, formatFloat(quote.ExecutedPrice()),
+				formatFloat(quote.FeeUSD), quote.Platform}
+		}
+		header := []string{"t_execute", "fx_quote_id", "pair", "side", "quantity", "token", "price", "fee_usd", "platform"}
+		return env.printer.print(quotes, header, rows)
+	}
+}
+
+func limitsCommand(fs *flag.FlagSet) func(env *environment) error {
+	platform := fs.String("platform", "api", "platform: api, browser or margin")
+
+	return func(env *environment) error {
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		limits, err := client.GetTradeLimits(*platform)
+		if err != nil {
+			return err
+		}
+		row := func(name string, limit clients.TradeLimit) []string {
+			return []string{name, formatFloat(limit.Total), formatFloat(limit.Used), formatFloat(limit.Available)}
+		}
+		rows := [][]string{row("gross", limits.GrossLimits), row("net", limits.NetLimits)}
+		return env.printer.print(limits, []string{"limit", "total", "used", "available"}, rows)
+	}
+}
+
+func sizesCommand(fs *flag.FlagSet) func(env *environment) error {
+	return func(env *environment) error {
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		sizes, err := client.GetTradeSizes()
+		if err != nil {
+			return err
+		}
+		rows := make([][]string, len(sizes))
+		for i, size := range sizes {
+			rows[i] = []string{size.TokenPair.BaseToken + "/" + size.TokenPair.QuoteToken, size.Platform,
+				formatFloat(size.TradeSizeLimitQuoteToken.Min), formatFloat(size.TradeSizeLimitQuoteToken.Max)}
+		}
+		return env.printer.print(sizes, []string{"pair", "platform", "min_quote", "max_quote"}, rows)
+	}
+}
+
+func volumeCommand(fs *flag.FlagSet) func(env *environment) error {
+	start, end := rangeFlags(fs)
+
+	return func(env *environment) error {
+		from, to, err := parseRange(*start, *end)
+		if err != nil {
+			return err
+		}
+		client, err := env.restClient()
+		if err != nil {
+			return err
+		}
+		volume, err := client.GetTradeVolume(from, to)
+		if err != nil {
+			return err
+		}
+		rows := [][]string{{formatTime(volume.StartDate), formatTime(volume.EndDate), formatFloat(volume.USDVolume)}}
+		return env.printer.print(volume, []string{"start_date", "end_date", "usd_volume"}, rows)
+	}
+}
+
+func streamCommand(fs *flag.FlagSet) func(env *environment) error {
+	pair := fs.String("pair", "", "token pair, e.g. BTC/USD (required)")
+	quantities := fs.String("quantities", "", "comma separated ladder quantities in base token (required)")
+
+	return func(env *environment) error {
+		tokenPair, err := parsePair(*pair)
+		if err != nil {
+			return err
+		}
+		levels, err := parseQuantities(*quantities)
+		if err != nil {
+			return err
+		}
+		client, err := env.socketClient(streamingNamespace)
+		if err != nil {
+			return err
+		}
+		if err := client.Connect(); err != nil {
+			return err
+		}
+		defer client.Connection.Close()
+		client.Connection.ConnectNamespace(streamingNamespace)
+
+		ladders := make(chan clients.PriceLadder, 64)
+		if err := client.OnPriceLadder(func(ladder clients.PriceLadder) {
+			select {
+			case ladders <- ladder:
+			default:
+			}
+		}); err != nil {
+			return err
+		}
+		if err := client.Subscribe(clients.SubscriptionRequest{
+			TokenPair:       tokenPair,
+			Quantity:        levels,
+			ClientRequestID: clients.NewUUID(),
+		}); err != nil {
+			return err
+		}
+		defer client.UnsubscribeAll()
+
+		interrupted := make(chan os.Signal, 1)
+		signal.Notify(interrupted, os.Interrupt)
+		defer signal.Stop(interrupted)
+
+		for {
+			select {
+			case <-interrupted:
+				return nil
+			case ladder := <-ladders:
+				if err := printLadder(env, ladder); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func printQuote(env *environment, quote clients.QuoteResponse) error {
+	return env.printer.printRecord(quote, [][2]string{
+		{"fx_quote_id", quote.FxQuoteId},
+		{"client_order_id", quote.ClientOrderId},
+		{"status", quote.Status},
+		{"token_pair", quote.TokenPair.BaseToken + "/" + quote.TokenPair.QuoteToken},
+		{"quantity", formatFloat(quote.Quantity.Value) + " " + quote.Quantity.Token},
+		{"side_requested", quote.SideRequested},
+		{"buy_price", formatNullFloat(quote.BuyPrice)},
+		{"sell_price", formatNullFloat(quote.SellPrice)},
+		{"t_quote", formatTime(quote.QuoteTime)},
+		{"t_expiry", formatTime(quote.ExpiryTime)},
+		{"is_filled", strconv.FormatBool(quote.IsFilled)},
+		{"side_executed", quote.SideExecuted.Side},
+		{"price_executed", formatFloat(quote.ExecutedPrice())},
+		{"t_execute", formatTime(quote.ExecutionTime.Time)},
+	})
+}
+
+func printLadder(env *environment, ladder clients.PriceLadder) error {
+	rows := make([][]string, 0, len(ladder.Levels))
+	for _, level := range ladder.Sorted() {
+		rows = append(rows, []string{formatTime(ladder.QuoteTime),
+			ladder.TokenPair.BaseToken + "/" + ladder.TokenPair.QuoteToken,
+			formatFloat(level.Quantity), formatFloat(level.BuyPrice), formatFloat(level.SellPrice)})
+	}
+	return env.printer.print(ladder, []string{"t_quote", "pair", "quantity", "buy_price", "sell_price"}, rows)
+}
+
+func parsePair(value string) (clients.TokenPair, error) {
+	parts := strings.FieldsFunc(strings.ToUpper(value), func(r rune) bool { return r == '/' || r == '-' })
+	if len(parts) != 2 {
+		return clients.TokenPair{}, fmt.Errorf("invalid pair %q, expected e.g. BTC/USD", value)
+	}
+	return clients.TokenPair{BaseToken: parts[0], QuoteToken: parts[1]}, nil
+}
+
+func quantityToken(token string, pair clients.TokenPair) string {
+	if token == "" {
+		return pair.BaseToken
+	}
+	return strings.ToUpper(token)
+}
+
+func parseQuantities(value string) ([]float64, error) {
+	var result []float64
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		quantity, err := strconv.ParseFloat(field, 64)
+		if err != nil || quantity <= 0 {
+			return nil, fmt.Errorf("invalid quantity %q", field)
+		}
+		result = append(result, quantity)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("-quantities is required")
+	}
+	return result, nil
+}
+
+func orGenerated(clientOrderID string) string {
+	if clientOrderID == "" {
+		return clients.NewUUID()
+	}
+	return clientOrderID
+}
+
+func rangeFlags(fs *flag.FlagSet) (start, end *string) {
+	start = fs.String("start", "", "range start, RFC 3339 or YYYY-MM-DD (default 24h before end)")
+	end = fs.String("end", "", "range end, RFC 3339 or YYYY-MM-DD (default now)")
+	return start, end
+}
+
+func parseRange(start, end string) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if end != "" {
+		var err error
+		if to, err = parseTime(end); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	from := to.Add(-24 * time.Hour)
+	if start != "" {
+		var err error
+		if from, err = parseTime(start); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("-start must be before -end")
+	}
+	return from, to, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339 or YYYY-MM-DD", value)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/config"
+)
+
+// commonOptions are the flags every command accepts.
+type commonOptions struct {
+	config  string
+	profile string
+	output  string
+	yes     bool
+	dryRun  bool
+}
+
+func registerCommon(fs *flag.FlagSet, dangerous bool) *commonOptions {
+	options := &commonOptions{}
+	fs.StringVar(&options.config, "config", "", "profile file (default $FALCONX_CONFIG or ~/.falconx/config.toml)")
+	fs.StringVar(&options.profile, "profile", "", "profile name (default $FALCONX_PROFILE or the file's default_profile)")
+	fs.StringVar(&options.output, "o", "table", "output format: table, json or csv")
+	if dangerous {
+		fs.BoolVar(&options.yes, "yes", false, "do not ask for confirmation")
+		fs.BoolVar(&options.dryRun, "dry-run", false, "sign the request and print it instead of sending it")
+	}
+	return options
+}
+
+// environment is what a command runs against.
+type environment struct {
+	options *commonOptions
+	printer *printer
+	in      *bufio.Reader
+	out     io.Writer
+}
+
+func newEnvironment(options *commonOptions) (*environment, error) {
+	printer, err := newPrinter(os.Stdout, options.output)
+	if err != nil {
+		return nil, err
+	}
+	return &environment{
+		options: options,
+		printer: printer,
+		in:      bufio.NewReader(os.Stdin),
+		out:     os.Stderr,
+	}, nil
+}
+
+// profile loads the selected profile. It is only called by commands that talk to
+// FalconX, so flag errors are reported before credential errors.
+func (env *environment) profile() (config.Profile, error) {
+	return config.Load(config.Options{Path: env.options.config, Profile: env.options.profile})
+}
+
+func (env *environment) restClient() (*clients.RestClient, error) {
+	profile, err := env.profile()
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := profile.RestClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	restConfig.DryRun = env.options.dryRun
+	restConfig.DryRunHandler = func(request clients.DryRunRequest) {
+		fmt.Fprintf(env.out, "dry run: %s %s\n%s\n", request.Method, request.URL, request.Body)
+	}
+	return clients.NewRestClient(restConfig), nil
+}
+
+func (env *environment) socketClient(namespace string) (*clients.SocketClient, error) {
+	profile, err := env.profile()
+	if err != nil {
+		return nil, err
+	}
+	return profile.NewSocketClient(namespace)
+}
+
+// errAborted is returned when the operator declines a confirmation prompt.
+var errAborted = errors.New("aborted")
+
+// confirm asks the operator to approve action unless -yes or -dry-run was given.
+func (env *environment) confirm(action string) error {
+	if env.options.yes || env.options.dryRun {
+		return nil
+	}
+	fmt.Fprintf(env.out, "%s? [y/N] ", action)
+	answer, err := env.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errAborted
+}
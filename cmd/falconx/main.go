@@ -0,0 +1,102 @@
+// Command falconx is a command-line client for the FalconX API.
+//
+//	falconx <command> [flags]
+//
+// Credentials come from a profile loaded by the config package: -profile selects it from
+// the file given with -config (default ~/.falconx/config.toml), and FALCONX_* environment
+// variables take precedence. Commands that trade ask for confirmation unless -yes is given.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command is one falconx subcommand.
+type command struct {
+	usage string
+	// dangerous commands move funds and ask for confirmation.
+	dangerous bool
+	// flags registers the command's own flags and returns the function running it.
+	flags func(fs *flag.FlagSet) func(env *environment) error
+}
+
+var commands = map[string]command{
+	"pairs":          {usage: "list tradable token pairs", flags: pairsCommand},
+	"quote":          {usage: "request a quote", flags: quoteCommand},
+	"execute":        {usage: "execute a quote", dangerous: true, flags: executeCommand},
+	"order":          {usage: "place a market or limit order", dangerous: true, flags: orderCommand},
+	"quote-status":   {usage: "show the status of a quote", flags: quoteStatusCommand},
+	"balances":       {usage: "show balances", flags: balancesCommand},
+	"total-balances": {usage: "show balances across platforms", flags: totalBalancesCommand},
+	"transfers":      {usage: "list deposits and withdrawals", flags: transfersCommand},
+	"trades":         {usage: "list executed quotes", flags: tradesCommand},
+	"limits":         {usage: "show gross and net trade limits", flags: limitsCommand},
+	"sizes":          {usage: "show trade size limits per pair", flags: sizesCommand},
+	"volume":         {usage: "show USD trade volume", flags: volumeCommand},
+	"stream":         {usage: "stream price ladders", flags: streamCommand},
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "falconx:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		return flag.ErrHelp
+	}
+
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	options := registerCommon(fs, cmd.dangerous)
+	runCommand := cmd.flags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: falconx %s [flags]\n\n%s.\n\n", name, cmd.usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	env, err := newEnvironment(options)
+	if err != nil {
+		return err
+	}
+	return runCommand(env)
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: falconx <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Run "falconx <command> -h" for the flags of a command.`)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// printer renders results as an aligned table, JSON or CSV. JSON output is the raw API
+// value; table and CSV output use the command's columns.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case "table", "json", "csv":
+		return &printer{w: w, format: format}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+// print writes value as JSON, or header and rows as a table or CSV.
+func (printer *printer) print(value interface{}, header []string, rows [][]string) error {
+	switch printer.format {
+	case "json":
+		encoder := json.NewEncoder(printer.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case "csv":
+		writer := csv.NewWriter(printer.w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	default:
+		writer := tabwriter.NewWriter(printer.w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.ToUpper(strings.Join(header, "\t")))
+		for _, row := range rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	}
+}
+
+// printRecord prints a single value as field/value rows in table and CSV output.
+func (printer *printer) printRecord(value interface{}, fields [][2]string) error {
+	rows := make([][]string, len(fields))
+	for i, field := range fields {
+		rows[i] = []string{field[0], field[1]}
+	}
+	return printer.print(value, []string{"field", "value"}, rows)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatNullFloat leaves null values empty.
+func formatNullFloat(value clients.NullFloat64) string {
+	if !value.Valid {
+		return ""
+	}
+	return formatFloat(value.Float64)
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,459 @@
+// Package config loads FalconX credential profiles from a file and FALCONX_* environment
+// variables, and builds REST and WebSocket clients from them.
+//
+// Profiles live in a TOML file, ~/.falconx/config.toml by default:
+//
+//	default_profile = "prod"
+//
+//	[prod]
+//	api_key = "..."
+//	secret = "..."
+//	passphrase = "..."
+//
+//	[qa]
+//	environment = "qa"
+//	api_key = "..."
+//	secret = "..."
+//	passphrase = "..."
+//
+// A profile's environment (prod or qa) selects its REST base URL and WebSocket host.
+// Both can be set explicitly with base_url and ws_host; when only base_url is set the
+// WebSocket host is derived from it. qa has no published WebSocket host, and any other
+// environment, such as sandbox, has no published endpoints, so those profiles set them.
+// Environment variables override the selected profile.
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// Environment variables read by Load.
+const (
+	EnvConfig      = "FALCONX_CONFIG"
+	EnvProfile     = "FALCONX_PROFILE"
+	EnvEnvironment = "FALCONX_ENVIRONMENT"
+	EnvBaseURL     = "FALCONX_BASE_URL"
+	EnvWSHost      = "FALCONX_WS_HOST"
+	EnvAPIKey      = "FALCONX_API_KEY"
+	EnvSecret      = "FALCONX_SECRET"
+	EnvPassphrase  = "FALCONX_PASSPHRASE"
+	EnvSecretFile  = "FALCONX_SECRET_FILE"
+	EnvSigner      = "FALCONX_SIGNER_SOCKET"
+)
+
+// DefaultProfile is used when neither the caller, FALCONX_PROFILE nor the file's
+// default_profile name one.
+const DefaultProfile = "default"
+
+// Endpoints are the REST base URL and WebSocket host of a FalconX environment.
+type Endpoints struct {
+	BaseURL string
+	WSHost  string
+}
+
+// Environments maps environment names to their published endpoints. A profile selecting
+// an environment not listed here, such as sandbox, must set base_url, and one without a
+// WSHost must set ws_host to build a socket client.
+var Environments = map[string]Endpoints{
+	"prod": {BaseURL: "https://api.falconx.io", WSHost: "ws.falconx.io"},
+	"qa":   {BaseURL: "https://qa.falconxdev.com"},
+}
+
+// ErrProfileNotFound is returned when the requested profile is not in the file.
+var ErrProfileNotFound = errors.New("config: profile not found")
+
+// Profile is one set of credentials and endpoints.
+type Profile struct {
+	Name string
+	// Environment selects endpoints from Environments. Defaults to prod.
+	Environment string
+	BaseURL     string
+	WSHost      string
+	APIKey      string
+	Passphrase  string
+	// Exactly one of Secret, SecretFile and SignerSocket provides the signing key:
+	// SecretFile is read by a clients.FileSigner and SignerSocket is the Unix socket of a
+	// signing daemon, so the secret never enters this process. One set through the
+	// environment replaces whichever the file sets.
+	Secret       string
+	SecretFile   string
+	SignerSocket string
+}
+
+// Validate checks that every credential is present, that the secret is valid base64 and
+// that the endpoints are usable.
+func (profile Profile) Validate() error {
+	var missing []string
+	if profile.APIKey == "" {
+		missing = append(missing, "api_key")
+	}
+	if profile.Passphrase == "" {
+		missing = append(missing, "passphrase")
+	}
+	keys := 0
+	for _, key := range []string{profile.Secret, profile.SecretFile, profile.SignerSocket} {
+		if key != "" {
+			keys++
+		}
+	}
+	if keys == 0 {
+		missing = append(missing, "secret, secret_file or signer_socket")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("config: profile %q is missing %s", profile.Name, strings.Join(missing, ", "))
+	}
+	if keys > 1 {
+		return fmt.Errorf("config: profile %q sets more than one of secret, secret_file and signer_socket", profile.Name)
+	}
+	if profile.Secret != "" {
+		if _, err := base64.StdEncoding.DecodeString(profile.Secret); err != nil {
+			return fmt.Errorf("config: profile %q secret is not valid base64: %w", profile.Name, err)
+		}
+	}
+	if profile.SecretFile != "" {
+		if _, err := clients.NewFileSigner(profile.SecretFile); err != nil {
+			return fmt.Errorf("config: profile %q: %w", profile.Name, err)
+		}
+	}
+
+	parsed, err := url.Parse(profile.BaseURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return fmt.Errorf("config: profile %q has invalid base_url %q", profile.Name, profile.BaseURL)
+	}
+	if strings.Contains(profile.WSHost, "/") {
+		return fmt.Errorf("config: profile %q has invalid ws_host %q", profile.Name, profile.WSHost)
+	}
+	return nil
+}
+
+// Signer returns the signer for SecretFile or SignerSocket, or nil when the profile
+// holds the secret inline.
+func (profile Profile) Signer() (clients.Signer, error) {
+	switch {
+	case profile.SecretFile != "":
+		return clients.NewFileSigner(profile.SecretFile)
+	case profile.SignerSocket != "":
+		return clients.NewDaemonSigner(profile.SignerSocket, 0), nil
+	}
+	return nil, nil
+}
+
+// RestClientConfig returns the REST client configuration of the profile.
+func (profile Profile) RestClientConfig() (clients.RestClientConfig, error) {
+	signer, err := profile.Signer()
+	if err != nil {
+		return clients.RestClientConfig{}, err
+	}
+	return clients.RestClientConfig{
+		BaseURL:    profile.BaseURL,
+		APIKey:     profile.APIKey,
+		Secret:     profile.Secret,
+		Passphrase: profile.Passphrase,
+		Signer:     signer,
+	}, nil
+}
+
+// SocketClientConfig returns the WebSocket client configuration of the profile. It fails
+// when no WebSocket host is known for the profile.
+func (profile Profile) SocketClientConfig() (clients.SocketClientConfig, error) {
+	if profile.WSHost == "" {
+		return clients.SocketClientConfig{}, fmt.Errorf("config: profile %q has no ws_host", profile.Name)
+	}
+	signer, err := profile.Signer()
+	if err != nil {
+		return clients.SocketClientConfig{}, err
+	}
+	return clients.SocketClientConfig{
+		Host:       profile.WSHost,
+		APIKey:     profile.APIKey,
+		Secret:     profile.Secret,
+		Passphrase: profile.Passphrase,
+		Signer:     signer,
+	}, nil
+}
+
+// NewRestClient validates the profile and creates a REST client from it.
+func (profile Profile) NewRestClient() (*clients.RestClient, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	restConfig, err := profile.RestClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return clients.NewRestClient(restConfig), nil
+}
+
+// NewSocketClient validates the profile and creates a WebSocket client from it.
+func (profile Profile) NewSocketClient(namespace string) (*clients.SocketClient, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	socketConfig, err := profile.SocketClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return clients.NewSocketClient(socketConfig, namespace), nil
+}
+
+// resolveEndpoints fills BaseURL and WSHost from the environment, or derives WSHost from
+// an explicit BaseURL.
+func (profile *Profile) resolveEndpoints() error {
+	if profile.BaseURL != "" && profile.WSHost != "" {
+		return nil
+	}
+
+	if profile.BaseURL == "" {
+		name := profile.Environment
+		if name == "" {
+			name = "prod"
+		}
+		endpoints, ok := Environments[name]
+		if !ok {
+			return fmt.Errorf("config: profile %q selects environment %q, which has no published "+
+				"endpoints (known: %s); set base_url or %s", profile.Name, name, environmentNames(), EnvBaseURL)
+		}
+		profile.BaseURL = endpoints.BaseURL
+		if profile.WSHost == "" {
+			profile.WSHost = endpoints.WSHost
+		}
+		return nil
+	}
+
+	profile.WSHost = DeriveWSHost(profile.BaseURL)
+	return nil
+}
+
+// environmentNames returns the names of Environments, sorted and comma-separated.
+func environmentNames() string {
+	names := make([]string, 0, len(Environments))
+	for name := range Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// DeriveWSHost returns the WebSocket host matching a REST base URL: the host of a known
+// environment, empty when that environment has none published, or the REST host with a
+// leading "api." replaced by, or otherwise prefixed with, "ws.".
+func DeriveWSHost(baseURL string) string {
+	trimmed := strings.TrimRight(baseURL, "/")
+	for _, endpoints := range Environments {
+		if endpoints.BaseURL == trimmed {
+			return endpoints.WSHost
+		}
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	if strings.HasPrefix(parsed.Host, "api.") {
+		return "ws." + strings.TrimPrefix(parsed.Host, "api.")
+	}
+	return "ws." + parsed.Host
+}
+
+// File is a parsed profile file.
+type File struct {
+	DefaultProfile string
+	Profiles       map[string]Profile
+}
+
+// Names returns the profile names, sorted.
+func (file *File) Names() []string {
+	names := make([]string, 0, len(file.Profiles))
+	for name := range file.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseFile parses a profile file. Tables may be written [name] or [profiles.name], and
+// names may be quoted, as in ["eu.prod"].
+func ParseFile(r io.Reader) (*File, error) {
+	top, tables, err := parseTOML(r)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	file := &File{Profiles: make(map[string]Profile)}
+	for key, value := range top {
+		switch key {
+		case "default_profile":
+			file.DefaultProfile = value
+		default:
+			return nil, fmt.Errorf("config: unknown top-level key %q", key)
+		}
+	}
+
+	for _, table := range tables {
+		var name string
+		switch {
+		case len(table.name) == 1:
+			name = table.name[0]
+		case len(table.name) == 2 && table.name[0] == "profiles":
+			name = table.name[1]
+		default:
+			return nil, fmt.Errorf("config: unexpected table [%s]", strings.Join(table.name, "."))
+		}
+		profile := Profile{Name: name}
+		for key, value := range table.values {
+			field, ok := profileField(&profile, key)
+			if !ok {
+				return nil, fmt.Errorf("config: unknown key %q in profile %q", key, name)
+			}
+			*field = value
+		}
+		if _, exists := file.Profiles[name]; exists {
+			return nil, fmt.Errorf("config: profile %q defined twice", name)
+		}
+		file.Profiles[name] = profile
+	}
+	return file, nil
+}
+
+// LoadFile reads and parses the profile file at path.
+func LoadFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	file, err := ParseFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+// DefaultPath returns ~/.falconx/config.toml.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".falconx", "config.toml")
+}
+
+// Options select what Load reads. Empty fields fall back to FALCONX_CONFIG and
+// FALCONX_PROFILE, then to the defaults.
+type Options struct {
+	// Path of the profile file. A missing file at the default path is not an error, so
+	// credentials can come from the environment alone.
+	Path string
+	// Profile is the profile to load.
+	Profile string
+	// Getenv reads environment variables. Defaults to os.Getenv.
+	Getenv func(string) string
+}
+
+// Load reads the selected profile from the profile file, applies FALCONX_* environment
+// overrides, resolves its endpoints and validates it.
+func Load(options Options) (Profile, error) {
+	getenv := options.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	path, explicit := options.Path, options.Path != ""
+	if path == "" {
+		path, explicit = getenv(EnvConfig), getenv(EnvConfig) != ""
+	}
+	if path == "" {
+		path = DefaultPath()
+	}
+
+	file := &File{Profiles: map[string]Profile{}}
+	if path != "" {
+		loaded, err := LoadFile(path)
+		switch {
+		case err == nil:
+			file = loaded
+		case !os.IsNotExist(err) || explicit:
+			return Profile{}, fmt.Errorf("config: %w", err)
+		}
+	}
+
+	name := options.Profile
+	if name == "" {
+		name = getenv(EnvProfile)
+	}
+	explicitProfile := name != ""
+	if name == "" {
+		name = file.DefaultProfile
+		explicitProfile = name != ""
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profile, ok := file.Profiles[name]
+	if !ok {
+		if explicitProfile {
+			return Profile{}, fmt.Errorf("%w: %q (available: %s)", ErrProfileNotFound, name, strings.Join(file.Names(), ", "))
+		}
+		profile = Profile{Name: name}
+	}
+
+	// A signing key from the environment replaces the file's, whichever kind it is.
+	if getenv(EnvSecret) != "" || getenv(EnvSecretFile) != "" || getenv(EnvSigner) != "" {
+		profile.Secret, profile.SecretFile, profile.SignerSocket = "", "", ""
+	}
+	for variable, field := range map[string]*string{
+		EnvEnvironment: &profile.Environment,
+		EnvBaseURL:     &profile.BaseURL,
+		EnvWSHost:      &profile.WSHost,
+		EnvAPIKey:      &profile.APIKey,
+		EnvSecret:      &profile.Secret,
+		EnvPassphrase:  &profile.Passphrase,
+		EnvSecretFile:  &profile.SecretFile,
+		EnvSigner:      &profile.SignerSocket,
+	} {
+		if value := getenv(variable); value != "" {
+			*field = value
+		}
+	}
+
+	if err := profile.resolveEndpoints(); err != nil {
+		return Profile{}, err
+	}
+	if err := profile.Validate(); err != nil {
+		return Profile{}, err
+	}
+	return profile, nil
+}
+
+func profileField(profile *Profile, key string) (*string, bool) {
+	switch key {
+	case "environment":
+		return &profile.Environment, true
+	case "base_url":
+		return &profile.BaseURL, true
+	case "ws_host":
+		return &profile.WSHost, true
+	case "api_key":
+		return &profile.APIKey, true
+	case "secret":
+		return &profile.Secret, true
+	case "passphrase":
+		return &profile.Passphrase, true
+	case "secret_file":
+		return &profile.SecretFile, true
+	case "signer_socket":
+		return &profile.SignerSocket, true
+	}
+	return nil, false
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProfiles(t *testing.T, contents string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "falconx-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func environment(values map[string]string) func(string) string {
+	return func(name string) string { return values[name] }
+}
+
+func TestParseFileQuotedTables(t *testing.T) {
+	file, err := ParseFile(strings.NewReader(`
+["eu prod"]
+api_key = "a"
+
+[profiles."eu.qa"]
+api_key = "b"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := file.Profiles["eu prod"].APIKey; got != "a" {
+		t.Errorf(`profile "eu prod" api_key = %q, want "a"`, got)
+	}
+	if got := file.Profiles["eu.qa"].APIKey; got != "b" {
+		t.Errorf(`profile "eu.qa" api_key = %q, want "b"`, got)
+	}
+
+	if _, err := ParseFile(strings.NewReader("[a.b.c]\n")); err == nil {
+		t.Error("ParseFile accepted a nested table")
+	}
+}
+
+func TestLoadEnvironments(t *testing.T) {
+	path := writeProfiles(t, `
+[prod]
+api_key = "key"
+secret = "c2VjcmV0"
+passphrase = "pass"
+
+[qa]
+environment = "qa"
+api_key = "key"
+secret = "c2VjcmV0"
+passphrase = "pass"
+
+[sandbox]
+environment = "sandbox"
+api_key = "key"
+secret = "c2VjcmV0"
+passphrase = "pass"
+
+[sandbox-configured]
+environment = "sandbox"
+base_url = "https://api.sandbox.example.com"
+api_key = "key"
+secret = "c2VjcmV0"
+passphrase = "pass"
+`)
+
+	for _, test := range []struct {
+		profile, baseURL, wsHost string
+	}{
+		{"prod", "https://api.falconx.io", "ws.falconx.io"},
+		{"qa", "https://qa.falconxdev.com", ""},
+		{"sandbox-configured", "https://api.sandbox.example.com", "ws.sandbox.example.com"},
+	} {
+		profile, err := Load(Options{Path: path, Profile: test.profile, Getenv: environment(nil)})
+		if err != nil {
+			t.Errorf("Load(%s): %v", test.profile, err)
+			continue
+		}
+		if profile.BaseURL != test.baseURL || profile.WSHost != test.wsHost {
+			t.Errorf("Load(%s) endpoints = %q, %q, want %q, %q", test.profile, profile.BaseURL, profile.WSHost, test.baseURL, test.wsHost)
+		}
+	}
+
+	_, err := Load(Options{Path: path, Profile: "sandbox", Getenv: environment(nil)})
+	if err == nil || !strings.Contains(err.Error(), `environment "sandbox", which has no published endpoints`) ||
+		!strings.Contains(err.Error(), "set base_url or FALCONX_BASE_URL") {
+		t.Errorf("Load(sandbox) error = %v, want one asking for base_url", err)
+	}
+
+	qa, err := Load(Options{Path: path, Profile: "qa", Getenv: environment(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qa.SocketClientConfig(); err == nil {
+		t.Error("SocketClientConfig succeeded for qa without a ws_host")
+	}
+}
+
+func TestLoadEnvironmentSecretOverridesFile(t *testing.T) {
+	path := writeProfiles(t, `
+default_profile = "prod"
+
+[prod]
+api_key = "key"
+secret_file = "/nonexistent/secret"
+passphrase = "pass"
+`)
+
+	profile, err := Load(Options{Path: path, Getenv: environment(map[string]string{EnvSecret: "ZW52"})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Secret != "ZW52" || profile.SecretFile != "" || profile.SignerSocket != "" {
+		t.Errorf("secret, secret_file, signer_socket = %q, %q, %q, want only the environment secret",
+			profile.Secret, profile.SecretFile, profile.SignerSocket)
+	}
+
+	profile, err = Load(Options{Path: path, Getenv: environment(map[string]string{EnvSigner: "/run/signer.sock"})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.SignerSocket != "/run/signer.sock" || profile.SecretFile != "" {
+		t.Errorf("signer_socket, secret_file = %q, %q, want only the environment socket", profile.SignerSocket, profile.SecretFile)
+	}
+
+	_, err = Load(Options{Path: path, Getenv: environment(map[string]string{EnvSecret: "ZW52", EnvSigner: "/run/signer.sock"})})
+	if err == nil || !strings.Contains(err.Error(), "more than one") {
+		t.Errorf("Load with two environment keys: err = %v, want more than one", err)
+	}
+}
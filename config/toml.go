@@ -0,0 +1,177 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// tomlTable is a [table] of a profile file. Its name holds the parts of a dotted
+// header, so [profiles."eu.prod"] is {"profiles", "eu.prod"}.
+type tomlTable struct {
+	name   []string
+	values map[string]string
+}
+
+// parseTOML parses the subset of TOML used by profile files: top-level keys, [table]
+// headers whose names are bare, quoted or dotted keys, and key = value pairs whose
+// values are strings, booleans or numbers. Values are returned as strings; the top-level
+// keys separately from the tables, which keep the order of the file.
+func parseTOML(r io.Reader) (map[string]string, []tomlTable, error) {
+	top := make(map[string]string)
+	var tables []tomlTable
+	values := top
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if strings.HasPrefix(line, "[[") {
+				return nil, nil, fmt.Errorf("line %d: arrays of tables are not supported", lineNumber)
+			}
+			name, rest, err := parseKey(line[1:])
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: invalid table header: %v", lineNumber, err)
+			}
+			if !strings.HasPrefix(rest, "]") || !isComment(rest[1:]) {
+				return nil, nil, fmt.Errorf("line %d: invalid table header", lineNumber)
+			}
+			id := fmt.Sprintf("%q", name)
+			if seen[id] {
+				return nil, nil, fmt.Errorf("line %d: table [%s] defined twice", lineNumber, strings.Join(name, "."))
+			}
+			seen[id] = true
+			values = make(map[string]string)
+			tables = append(tables, tomlTable{name: name, values: values})
+			continue
+		}
+
+		key, rest, err := parseKey(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		if len(key) != 1 {
+			return nil, nil, fmt.Errorf("line %d: dotted keys are not supported", lineNumber)
+		}
+		if !strings.HasPrefix(rest, "=") {
+			return nil, nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		value, err := parseValue(strings.TrimSpace(rest[1:]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		if _, exists := values[key[0]]; exists {
+			return nil, nil, fmt.Errorf("line %d: key %s defined twice", lineNumber, key[0])
+		}
+		values[key[0]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return top, tables, nil
+}
+
+// parseKey reads a bare, quoted or dotted key from the start of text, and returns its
+// parts and the text after it.
+func parseKey(text string) ([]string, string, error) {
+	var parts []string
+	for {
+		text = strings.TrimLeft(text, " \t")
+		var part string
+		var err error
+		switch {
+		case strings.HasPrefix(text, `"`):
+			part, text, err = basicString(text)
+		case strings.HasPrefix(text, "'"):
+			part, text, err = literalString(text)
+		default:
+			end := 0
+			for end < len(text) && isBareKeyByte(text[end]) {
+				end++
+			}
+			if end == 0 {
+				return nil, "", fmt.Errorf("invalid key")
+			}
+			part, text = text[:end], text[end:]
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		parts = append(parts, part)
+
+		text = strings.TrimLeft(text, " \t")
+		if !strings.HasPrefix(text, ".") {
+			return parts, text, nil
+		}
+		text = text[1:]
+	}
+}
+
+func isBareKeyByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// basicString reads a double-quoted string with escapes from the start of text.
+func basicString(text string) (string, string, error) {
+	for end := 1; end < len(text); end++ {
+		switch text[end] {
+		case '\\':
+			end++
+		case '"':
+			value, err := strconv.Unquote(text[:end+1])
+			if err != nil {
+				return "", "", fmt.Errorf("invalid string %s", text[:end+1])
+			}
+			return value, text[end+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+// literalString reads a single-quoted string, which has no escapes, from the start of
+// text.
+func literalString(text string) (string, string, error) {
+	end := strings.Index(text[1:], "'")
+	if end < 0 {
+		return "", "", fmt.Errorf("unterminated string")
+	}
+	return text[1 : end+1], text[end+2:], nil
+}
+
+func parseValue(raw string) (string, error) {
+	var value, rest string
+	var err error
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		value, rest, err = basicString(raw)
+	case strings.HasPrefix(raw, "'"):
+		value, rest, err = literalString(raw)
+	default:
+		if hash := strings.Index(raw, "#"); hash >= 0 {
+			raw = strings.TrimSpace(raw[:hash])
+		}
+		if raw == "" || strings.ContainsAny(raw, " \t") {
+			return "", fmt.Errorf("invalid value %q", raw)
+		}
+		return raw, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !isComment(rest) {
+		return "", fmt.Errorf("unexpected text after value")
+	}
+	return value, nil
+}
+
+func isComment(rest string) bool {
+	rest = strings.TrimSpace(rest)
+	return rest == "" || strings.HasPrefix(rest, "#")
+}
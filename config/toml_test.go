@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	input := `
+# profiles
+default_profile = "prod" # trailing comment
+
+[prod]
+api_key = "key"
+secret = 'c2VjcmV0'
+"quoted key" = "a \"quoted\" value\twith escapes"
+ratio = 0.5
+
+["eu prod"]
+api_key = "eu"
+
+[ profiles . "eu.prod" ]
+api_key = "dotted"
+`
+	top, tables, err := parseTOML(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"default_profile": "prod"}; !reflect.DeepEqual(top, want) {
+		t.Errorf("top = %v, want %v", top, want)
+	}
+	want := []tomlTable{
+		{name: []string{"prod"}, values: map[string]string{
+			"api_key":    "key",
+			"secret":     "c2VjcmV0",
+			"quoted key": "a \"quoted\" value\twith escapes",
+			"ratio":      "0.5",
+		}},
+		{name: []string{"eu prod"}, values: map[string]string{"api_key": "eu"}},
+		{name: []string{"profiles", "eu.prod"}, values: map[string]string{"api_key": "dotted"}},
+	}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("tables = %v, want %v", tables, want)
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	for _, input := range []string{
+		"[prod",
+		"[prod] extra",
+		"[\"prod]",
+		"[[prod]]",
+		"[prod]\n[prod]",
+		"[prod]\n[\"prod\"]",
+		"key",
+		"a.b = 1",
+		"key = \"unterminated",
+		"key = 'unterminated",
+		"key = \"value\" extra",
+		"key = two words",
+		"key = ",
+		"key = 1\nkey = 2",
+		"= value",
+	} {
+		if _, _, err := parseTOML(strings.NewReader(input)); err == nil {
+			t.Errorf("parseTOML(%q) succeeded, want an error", input)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// Credentials returns the profile's credentials for a clients.CredentialsProvider.
+func (profile Profile) Credentials() (clients.Credentials, error) {
+	signer, err := profile.Signer()
+	if err != nil {
+		return clients.Credentials{}, err
+	}
+	return clients.Credentials{
+		APIKey:     profile.APIKey,
+		Secret:     profile.Secret,
+		Passphrase: profile.Passphrase,
+		Signer:     signer,
+	}, nil
+}
+
+// NewRotatingCredentials validates the profile and creates a rotating provider starting
+// with its credentials.
+func (profile Profile) NewRotatingCredentials() (*clients.RotatingCredentials, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	credentials, err := profile.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	return clients.NewRotatingCredentials(credentials)
+}
+
+// WatchCredentials reloads the profile selected by options every interval until ctx is
+// done and rotates credentials whenever its api_key, secret, passphrase, secret_file or
+// signer_socket change. A profile that fails to load or validate is logged and skipped,
+// leaving the current credentials in place.
+func WatchCredentials(ctx context.Context, options Options, credentials *clients.RotatingCredentials, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, err := Load(options)
+	if err != nil {
+		log.Printf("Error loading credentials: %v", err)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		profile, err := Load(options)
+		if err != nil {
+			log.Printf("Error reloading credentials: %v", err)
+			continue
+		}
+		if sameCredentials(profile, last) {
+			continue
+		}
+		next, err := profile.Credentials()
+		if err == nil {
+			err = credentials.Rotate(next, "profile "+profile.Name+" changed")
+		}
+		if err != nil {
+			log.Printf("Error rotating credentials: %v", err)
+			continue
+		}
+		last = profile
+	}
+}
+
+func sameCredentials(a, b Profile) bool {
+	return a.APIKey == b.APIKey && a.Secret == b.Secret && a.Passphrase == b.Passphrase &&
+		a.SecretFile == b.SecretFile && a.SignerSocket == b.SignerSocket
+}
@@ -0,0 +1,280 @@
+// Package export writes trade history, transfers, balances and trade volume to CSV or
+// Parquet with a stable column schema.
+//
+// Timestamps are normalized to UTC. The Exporter streams long time ranges through the
+// adaptive window iterators of the clients package, writing rows in batches.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/export/parquet"
+)
+
+// Format selects the output file format.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	Parquet Format = "parquet"
+)
+
+// QuoteColumns is the schema of executed quote exports.
+var QuoteColumns = []parquet.Column{
+	{Name: "fx_quote_id", Type: parquet.String},
+	{Name: "client_order_id", Type: parquet.String},
+	{Name: "status", Type: parquet.String},
+	{Name: "platform", Type: parquet.String},
+	{Name: "base_token", Type: parquet.String},
+	{Name: "quote_token", Type: parquet.String},
+	{Name: "quantity", Type: parquet.Double},
+	{Name: "quantity_token", Type: parquet.String},
+	{Name: "side_requested", Type: parquet.String},
+	{Name: "side_executed", Type: parquet.String, Optional: true},
+	{Name: "is_filled", Type: parquet.Boolean},
+	{Name: "buy_price", Type: parquet.Double, Optional: true},
+	{Name: "sell_price", Type: parquet.Double, Optional: true},
+	{Name: "price_executed", Type: parquet.Double},
+	{Name: "gross_fee_bps", Type: parquet.Double},
+	{Name: "gross_fee_usd", Type: parquet.Double},
+	{Name: "rebate_bps", Type: parquet.Double},
+	{Name: "rebate_usd", Type: parquet.Double},
+	{Name: "fee_bps", Type: parquet.Double},
+	{Name: "fee_usd", Type: parquet.Double},
+	{Name: "t_quote", Type: parquet.Timestamp, Optional: true},
+	{Name: "t_expiry", Type: parquet.Timestamp, Optional: true},
+	{Name: "t_execute", Type: parquet.Timestamp, Optional: true},
+	{Name: "trader_email", Type: parquet.String},
+}
+
+// TransferColumns is the schema of transfer exports.
+var TransferColumns = []parquet.Column{
+	{Name: "type", Type: parquet.String},
+	{Name: "platform", Type: parquet.String},
+	{Name: "token", Type: parquet.String},
+	{Name: "quantity", Type: parquet.Double},
+	{Name: "status", Type: parquet.String},
+	{Name: "t_create", Type: parquet.Timestamp, Optional: true},
+}
+
+// BalanceColumns is the schema of balance exports.
+var BalanceColumns = []parquet.Column{
+	{Name: "token", Type: parquet.String},
+	{Name: "platform", Type: parquet.String},
+	{Name: "balance", Type: parquet.Double},
+	{Name: "t_as_of", Type: parquet.Timestamp},
+}
+
+// TradeVolumeColumns is the schema of trade volume exports.
+var TradeVolumeColumns = []parquet.Column{
+	{Name: "start_date", Type: parquet.Timestamp},
+	{Name: "end_date", Type: parquet.Timestamp},
+	{Name: "usd_volume", Type: parquet.Double},
+}
+
+func quoteRow(quote clients.QuoteResponse) []interface{} {
+	return []interface{}{
+		quote.FxQuoteId,
+		quote.ClientOrderId,
+		quote.Status,
+		quote.Platform,
+		quote.TokenPair.BaseToken,
+		quote.TokenPair.QuoteToken,
+		quote.Quantity.Value,
+		quote.Quantity.Token,
+		quote.SideRequested,
+		nullSide(quote.SideExecuted),
+		quote.IsFilled,
+		nullFloat(quote.BuyPrice),
+		nullFloat(quote.SellPrice),
+		quote.ExecutedPrice(),
+		quote.GrossFeeBps,
+		quote.GrossFeeUSD,
+		quote.RebateBps,
+		quote.RebateUSD,
+		quote.FeeBps,
+		quote.FeeUSD,
+		utc(quote.QuoteTime),
+		utc(quote.ExpiryTime),
+		utc(quote.ExecutionTime.Time),
+		quote.TraderEmail,
+	}
+}
+
+func transferRow(transfer clients.Transfer) []interface{} {
+	return []interface{}{
+		transfer.Type,
+		transfer.Platform,
+		transfer.Token,
+		transfer.Quantity,
+		transfer.Status,
+		utc(transfer.CreateTime),
+	}
+}
+
+func balanceRow(balance clients.Balance, asOf time.Time) []interface{} {
+	return []interface{}{balance.Token, balance.Platform, balance.Balance, utc(asOf)}
+}
+
+func tradeVolumeRow(volume clients.TradeVolume) []interface{} {
+	return []interface{}{utc(volume.StartDate), utc(volume.EndDate), volume.USDVolume}
+}
+
+// WriteQuotes writes quotes, e.g. the result of GetExecutedQuotes.
+func WriteQuotes(w io.Writer, format Format, quotes []clients.QuoteResponse) error {
+	rows := make([][]interface{}, len(quotes))
+	for i, quote := range quotes {
+		rows[i] = quoteRow(quote)
+	}
+	return writeAll(w, format, QuoteColumns, rows)
+}
+
+// WriteTransfers writes transfers, e.g. the result of GetTransfers.
+func WriteTransfers(w io.Writer, format Format, transfers []clients.Transfer) error {
+	rows := make([][]interface{}, len(transfers))
+	for i, transfer := range transfers {
+		rows[i] = transferRow(transfer)
+	}
+	return writeAll(w, format, TransferColumns, rows)
+}
+
+// WriteBalances writes balances read at asOf.
+func WriteBalances(w io.Writer, format Format, balances []clients.Balance, asOf time.Time) error {
+	rows := make([][]interface{}, len(balances))
+	for i, balance := range balances {
+		rows[i] = balanceRow(balance, asOf)
+	}
+	return writeAll(w, format, BalanceColumns, rows)
+}
+
+// WriteTradeVolumes writes trade volumes, one row per period.
+func WriteTradeVolumes(w io.Writer, format Format, volumes []clients.TradeVolume) error {
+	rows := make([][]interface{}, len(volumes))
+	for i, volume := range volumes {
+		rows[i] = tradeVolumeRow(volume)
+	}
+	return writeAll(w, format, TradeVolumeColumns, rows)
+}
+
+func writeAll(w io.Writer, format Format, columns []parquet.Column, rows [][]interface{}) error {
+	table, err := newTable(w, format, columns)
+	if err != nil {
+		return err
+	}
+	if err := table.write(rows); err != nil {
+		return err
+	}
+	return table.close()
+}
+
+// table receives rows in batches. For Parquet every batch becomes a row group.
+type table interface {
+	write(rows [][]interface{}) error
+	close() error
+}
+
+func newTable(w io.Writer, format Format, columns []parquet.Column) (table, error) {
+	switch format {
+	case CSV:
+		writer := csv.NewWriter(w)
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = column.Name
+		}
+		if err := writer.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvTable{writer: writer}, nil
+	case Parquet:
+		writer, err := parquet.NewWriter(w, columns)
+		if err != nil {
+			return nil, err
+		}
+		return &parquetTable{writer: writer}, nil
+	default:
+		return nil, fmt.Errorf("export: unknown format %q", format)
+	}
+}
+
+type csvTable struct {
+	writer *csv.Writer
+}
+
+func (table *csvTable) write(rows [][]interface{}) error {
+	record := make([]string, 0)
+	for _, row := range rows {
+		record = record[:0]
+		for _, value := range row {
+			record = append(record, formatCSV(value))
+		}
+		if err := table.writer.Write(record); err != nil {
+			return err
+		}
+	}
+	table.writer.Flush()
+	return table.writer.Error()
+}
+
+func (table *csvTable) close() error {
+	table.writer.Flush()
+	return table.writer.Error()
+}
+
+type parquetTable struct {
+	writer *parquet.Writer
+}
+
+func (table *parquetTable) write(rows [][]interface{}) error {
+	return table.writer.WriteRowGroup(rows)
+}
+
+func (table *parquetTable) close() error {
+	return table.writer.Close()
+}
+
+func formatCSV(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// nullFloat and nullSide return nil for null values, which optional columns accept.
+func nullFloat(value clients.NullFloat64) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.Float64
+}
+
+func nullSide(side clients.NullSide) interface{} {
+	if !side.Valid {
+		return nil
+	}
+	return side.Side
+}
+
+func utc(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC()
+}
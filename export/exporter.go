@@ -0,0 +1,157 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+const (
+	defaultWindow       = 24 * time.Hour
+	defaultRowGroupSize = 10000
+)
+
+// Config configures an Exporter.
+type Config struct {
+	Format Format
+	// Window is the period of each trade volume row. Defaults to one day.
+	Window time.Duration
+	// History tunes the adaptive windows quotes and transfers are fetched in.
+	History clients.HistoryConfig
+	// RowGroupSize is the number of rows buffered before they are written, which is also
+	// the Parquet row group size. Defaults to 10000.
+	RowGroupSize int
+	// Now returns the time balances are stamped with. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Exporter fetches history from FalconX and writes it in the configured format.
+type Exporter struct {
+	client clients.FalconX
+	config Config
+}
+
+// NewExporter creates an Exporter on top of client.
+func NewExporter(client clients.FalconX, config Config) *Exporter {
+	if config.Format == "" {
+		config.Format = CSV
+	}
+	if config.Window <= 0 {
+		config.Window = defaultWindow
+	}
+	if config.RowGroupSize <= 0 {
+		config.RowGroupSize = defaultRowGroupSize
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Exporter{client: client, config: config}
+}
+
+// ExecutedQuotes writes every quote executed in [start, end). Quotes are streamed through
+// ExecutedQuotesIterWith, so they never all sit in memory. It returns the number of rows
+// written.
+func (exporter *Exporter) ExecutedQuotes(ctx context.Context, w io.Writer, start, end time.Time) (int, error) {
+	table, err := newTable(w, exporter.config.Format, QuoteColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	iter := clients.ExecutedQuotesIterWith(ctx, exporter.client, start, end, exporter.config.History)
+	written, err := exporter.stream(table, iter.Next, func() []interface{} { return quoteRow(iter.Quote()) })
+	if err == nil {
+		err = iter.Err()
+	}
+	if err != nil {
+		return written, fmt.Errorf("export: executed quotes: %w", err)
+	}
+	return written, table.close()
+}
+
+// Transfers writes every transfer created in [start, end), streamed through
+// TransfersIterWith.
+func (exporter *Exporter) Transfers(ctx context.Context, w io.Writer, start, end time.Time) (int, error) {
+	table, err := newTable(w, exporter.config.Format, TransferColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	iter := clients.TransfersIterWith(ctx, exporter.client, start, end, exporter.config.History)
+	written, err := exporter.stream(table, iter.Next, func() []interface{} { return transferRow(iter.Transfer()) })
+	if err == nil {
+		err = iter.Err()
+	}
+	if err != nil {
+		return written, fmt.Errorf("export: transfers: %w", err)
+	}
+	return written, table.close()
+}
+
+// stream writes rows to table in batches of RowGroupSize.
+func (exporter *Exporter) stream(table table, next func() bool, row func() []interface{}) (int, error) {
+	written := 0
+	batch := make([][]interface{}, 0, exporter.config.RowGroupSize)
+	for next() {
+		batch = append(batch, row())
+		if len(batch) == exporter.config.RowGroupSize {
+			if err := table.write(batch); err != nil {
+				return written, err
+			}
+			written += len(batch)
+			batch = batch[:0]
+		}
+	}
+	if err := table.write(batch); err != nil {
+		return written, err
+	}
+	return written + len(batch), nil
+}
+
+// Balances writes the current balances stamped with the time they were read.
+func (exporter *Exporter) Balances(w io.Writer) (int, error) {
+	balances, err := exporter.client.GetBalances()
+	if err != nil {
+		return 0, fmt.Errorf("export: balances: %w", err)
+	}
+	return len(balances), WriteBalances(w, exporter.config.Format, balances, exporter.config.Now())
+}
+
+// TradeVolume writes the USD trade volume of every window in [start, end).
+func (exporter *Exporter) TradeVolume(w io.Writer, start, end time.Time) (int, error) {
+	var volumes []clients.TradeVolume
+	err := exporter.windows(start, end, func(from, to time.Time) error {
+		volume, err := exporter.client.GetTradeVolume(from, to)
+		if err != nil {
+			return err
+		}
+		volume.StartDate, volume.EndDate = from, to
+		volumes = append(volumes, volume)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("export: trade volume: %w", err)
+	}
+	return len(volumes), WriteTradeVolumes(w, exporter.config.Format, volumes)
+}
+
+// windows calls fetch for consecutive windows covering [start, end), in UTC.
+func (exporter *Exporter) windows(start, end time.Time, fetch func(from, to time.Time) error) error {
+	if !start.Before(end) {
+		return errors.New("start must be before end")
+	}
+	start, end = start.UTC(), end.UTC()
+	for from := start; from.Before(end); from = from.Add(exporter.config.Window) {
+		to := from.Add(exporter.config.Window)
+		if to.After(end) {
+			to = end
+		}
+		if err := fetch(from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+// Package parquet writes flat Parquet files using only the standard library.
+//
+// It supports the small subset the exporters need: a flat schema of required or optional
+// string, double, int64, boolean and timestamp columns, PLAIN encoding, no compression
+// and one data page per column chunk. Each call to WriteRowGroup writes one row group,
+// so large exports can be streamed without holding every row in memory.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+const magic = "PAR1"
+
+// createdBy is recorded in the file footer.
+const createdBy = "falconx-go"
+
+// Type is the logical type of a column.
+type Type int
+
+const (
+	// String columns take string values and are stored as UTF8 byte arrays.
+	String Type = iota
+	// Double columns take float64 values.
+	Double
+	// Int64 columns take int64 or int values.
+	Int64
+	// Boolean columns take bool values.
+	Boolean
+	// Timestamp columns take time.Time values and are stored as UTC microseconds. A zero
+	// time is written as null in optional columns.
+	Timestamp
+)
+
+// Parquet physical types, encodings and converted types from parquet.thrift.
+const (
+	physicalBoolean   = 0
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecUncompressed = 0
+	pageTypeData      = 0
+)
+
+// ErrClosed is returned when writing to a closed Writer.
+var ErrClosed = errors.New("parquet: writer closed")
+
+// Column describes one column of the schema.
+type Column struct {
+	Name string
+	Type Type
+	// Optional columns accept nil values.
+	Optional bool
+}
+
+// Writer writes rows to a Parquet file. Close must be called to write the footer.
+type Writer struct {
+	out     *countingWriter
+	columns []Column
+	groups  []rowGroup
+	rows    int64
+	started bool
+	closed  bool
+}
+
+type rowGroup struct {
+	rows   int64
+	chunks []columnChunk
+}
+
+type columnChunk struct {
+	values int64
+	offset int64
+	size   int64
+}
+
+type countingWriter struct {
+	w     io.Writer
+	count int64
+}
+
+func (writer *countingWriter) Write(p []byte) (int, error) {
+	n, err := writer.w.Write(p)
+	writer.count += int64(n)
+	return n, err
+}
+
+// NewWriter creates a Writer for columns writing to w.
+func NewWriter(w io.Writer, columns []Column) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("parquet: schema has no columns")
+	}
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if column.Name == "" || seen[column.Name] {
+			return nil, fmt.Errorf("parquet: invalid or duplicate column name %q", column.Name)
+		}
+		if column.Type < String || column.Type > Timestamp {
+			return nil, fmt.Errorf("parquet: column %s has unknown type %d", column.Name, column.Type)
+		}
+		seen[column.Name] = true
+	}
+	return &Writer{out: &countingWriter{w: w}, columns: columns}, nil
+}
+
+// WriteRowGroup writes rows as one row group. Each row holds one value per column, in
+// schema order. An empty slice writes nothing.
+func (writer *Writer) WriteRowGroup(rows [][]interface{}) error {
+	if writer.closed {
+		return ErrClosed
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	for i, row := range rows {
+		if len(row) != len(writer.columns) {
+			return fmt.Errorf("parquet: row %d has %d values, schema has %d columns", i, len(row), len(writer.columns))
+		}
+	}
+	if err := writer.start(); err != nil {
+		return err
+	}
+
+	group := rowGroup{rows: int64(len(rows))}
+	for i, column := range writer.columns {
+		page, err := encodeColumn(column, rows, i)
+		if err != nil {
+			return err
+		}
+		header := pageHeader(len(rows), page)
+
+		chunk := columnChunk{values: int64(len(rows)), offset: writer.out.count, size: int64(len(header) + len(page))}
+		if _, err := writer.out.Write(header); err != nil {
+			return err
+		}
+		if _, err := writer.out.Write(page); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+	}
+	writer.groups = append(writer.groups, group)
+	writer.rows += group.rows
+	return nil
+}
+
+// Close writes the file footer. It does not close the underlying writer.
+func (writer *Writer) Close() error {
+	if writer.closed {
+		return ErrClosed
+	}
+	if err := writer.start(); err != nil {
+		return err
+	}
+	writer.closed = true
+
+	footer := writer.fileMetaData()
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	for _, part := range [][]byte{footer, length[:], []byte(magic)} {
+		if _, err := writer.out.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (writer *Writer) start() error {
+	if writer.started {
+		return nil
+	}
+	writer.started = true
+	_, err := writer.out.Write([]byte(magic))
+	return err
+}
+
+// encodeColumn returns the data page body of column index: definition levels for
+// optional columns followed by the PLAIN encoded non-null values.
+func encodeColumn(column Column, rows [][]interface{}, index int) ([]byte, error) {
+	var page, values bytes.Buffer
+	levels := make([]byte, 0, len(rows))
+	var bits []bool
+
+	for i, row := range rows {
+		value := row[index]
+		if t, ok := value.(time.Time); ok && t.IsZero() && column.Optional {
+			value = nil
+		}
+		if value == nil {
+			if !column.Optional {
+				return nil, fmt.Errorf("parquet: row %d: required column %s is null", i, column.Name)
+			}
+			levels = append(levels, 0)
+			continue
+		}
+		levels = append(levels, 1)
+
+		var err error
+		switch column.Type {
+		case String:
+			s, ok := value.(string)
+			if !ok {
+				err = typeError(column, i, value)
+				break
+			}
+			binary.Write(&values, binary.LittleEndian, uint32(len(s)))
+			values.WriteString(s)
+		case Double:
+			f, ok := value.(float64)
+			if !ok {
+				err = typeError(column, i, value)
+				break
+			}
+			binary.Write(&values, binary.LittleEndian, math.Float64bits(f))
+		case Int64:
+			var n int64
+			switch v := value.(type) {
+			case int64:
+				n = v
+			case int:
+				n = int64(v)
+			default:
+				err = typeError(column, i, value)
+			}
+			binary.Write(&values, binary.LittleEndian, n)
+		case Boolean:
+			b, ok := value.(bool)
+			if !ok {
+				err = typeError(column, i, value)
+				break
+			}
+			bits = append(bits, b)
+		case Timestamp:
+			t, ok := value.(time.Time)
+			if !ok {
+				err = typeError(column, i, value)
+				break
+			}
+			binary.Write(&values, binary.LittleEndian, t.UnixNano()/int64(time.Microsecond))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if column.Type == Boolean {
+		packed := make([]byte, (len(bits)+7)/8)
+		for i, b := range bits {
+			if b {
+				packed[i/8] |= 1 << uint(i%8)
+			}
+		}
+		values.Write(packed)
+	}
+
+	if column.Optional {
+		encoded := encodeLevels(levels)
+		binary.Write(&page, binary.LittleEndian, uint32(len(encoded)))
+		page.Write(encoded)
+	}
+	page.Write(values.Bytes())
+	return page.Bytes(), nil
+}
+
+// encodeLevels encodes definition levels of bit width 1 with the RLE/bit-packing hybrid,
+// using only RLE runs.
+func encodeLevels(levels []byte) []byte {
+	var out bytes.Buffer
+	var scratch [binary.MaxVarintLen64]byte
+	for start := 0; start < len(levels); {
+		end := start
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		n := binary.PutUvarint(scratch[:], uint64(end-start)<<1)
+		out.Write(scratch[:n])
+		out.WriteByte(levels[start])
+		start = end
+	}
+	return out.Bytes()
+}
+
+func typeError(column Column, row int, value interface{}) error {
+	return fmt.Errorf("parquet: row %d: unexpected %T for column %s", row, value, column.Name)
+}
+
+func pageHeader(values int, page []byte) []byte {
+	var writer compactWriter
+	writer.begin()
+	writer.i32(1, pageTypeData)
+	writer.i32(2, int32(len(page)))
+	writer.i32(3, int32(len(page)))
+	writer.structField(5)
+	writer.i32(1, int32(values))
+	writer.i32(2, encodingPlain)
+	writer.i32(3, encodingRLE)
+	writer.i32(4, encodingRLE)
+	writer.end()
+	writer.end()
+	return writer.buf.Bytes()
+}
+
+func (writer *Writer) fileMetaData() []byte {
+	var meta compactWriter
+	meta.begin()
+	meta.i32(1, 1)
+
+	meta.list(2, compactStruct, len(writer.columns)+1)
+	meta.begin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(writer.columns)))
+	meta.end()
+	for _, column := range writer.columns {
+		physical, converted, hasConverted := physicalType(column.Type)
+		repetition := int32(repetitionRequired)
+		if column.Optional {
+			repetition = repetitionOptional
+		}
+		meta.begin()
+		meta.i32(1, physical)
+		meta.i32(3, repetition)
+		meta.binary(4, column.Name)
+		if hasConverted {
+			meta.i32(6, converted)
+		}
+		meta.end()
+	}
+
+	meta.i64(3, writer.rows)
+
+	meta.list(4, compactStruct, len(writer.groups))
+	for _, group := range writer.groups {
+		var total int64
+		meta.begin()
+		meta.list(1, compactStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			column := writer.columns[i]
+			physical, _, _ := physicalType(column.Type)
+			total += chunk.size
+
+			meta.begin()
+			meta.i64(2, chunk.offset)
+			meta.structField(3)
+			meta.i32(1, physical)
+			meta.list(2, compactI32, 2)
+			meta.i32Elem(encodingPlain)
+			meta.i32Elem(encodingRLE)
+			meta.list(3, compactBinary, 1)
+			meta.binaryElem(column.Name)
+			meta.i32(4, codecUncompressed)
+			meta.i64(5, chunk.values)
+			meta.i64(6, chunk.size)
+			meta.i64(7, chunk.size)
+			meta.i64(9, chunk.offset)
+			meta.end()
+			meta.end()
+		}
+		meta.i64(2, total)
+		meta.i64(3, group.rows)
+		meta.end()
+	}
+
+	meta.binary(6, createdBy)
+	meta.end()
+	return meta.buf.Bytes()
+}
+
+func physicalType(kind Type) (physical int32, converted int32, hasConverted bool) {
+	switch kind {
+	case String:
+		return physicalByteArray, convertedUTF8, true
+	case Double:
+		return physicalDouble, 0, false
+	case Int64:
+		return physicalInt64, 0, false
+	case Boolean:
+		return physicalBoolean, 0, false
+	default:
+		return physicalInt64, convertedTimestampMicros, true
+	}
+}
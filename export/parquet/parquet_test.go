@@ -0,0 +1,353 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// golden decodes a hex string, ignoring whitespace, so expected bytes can be annotated.
+func golden(t *testing.T, annotated string) []byte {
+	t.Helper()
+	var digits strings.Builder
+	for _, line := range strings.Split(annotated, "\n") {
+		if comment := strings.Index(line, "//"); comment >= 0 {
+			line = line[:comment]
+		}
+		digits.WriteString(strings.Join(strings.Fields(line), ""))
+	}
+	decoded, err := hex.DecodeString(digits.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestEncodeLevels(t *testing.T) {
+	for _, test := range []struct {
+		levels []byte
+		want   []byte
+	}{
+		{[]byte{1, 1, 0, 1}, []byte{0x04, 0x01, 0x02, 0x00, 0x02, 0x01}},
+		{bytes.Repeat([]byte{1}, 100), []byte{0xc8, 0x01, 0x01}},
+		{nil, nil},
+	} {
+		if got := encodeLevels(test.levels); !bytes.Equal(got, test.want) {
+			t.Errorf("encodeLevels(%v) = % x, want % x", test.levels, got, test.want)
+		}
+	}
+}
+
+func TestPageHeader(t *testing.T) {
+	want := golden(t, `
+		15 00 // type: DATA_PAGE
+		15 14 // uncompressed_page_size: 10
+		15 14 // compressed_page_size: 10
+		2c    // data_page_header
+		   15 06 // num_values: 3
+		   15 00 // encoding: PLAIN
+		   15 06 // definition_level_encoding: RLE
+		   15 06 // repetition_level_encoding: RLE
+		   00
+		00`)
+	if got := pageHeader(3, make([]byte, 10)); !bytes.Equal(got, want) {
+		t.Errorf("pageHeader = % x, want % x", got, want)
+	}
+}
+
+func TestFooter(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewWriter(&out, []Column{{Name: "n", Type: Int64}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteRowGroup([][]interface{}{{int64(7)}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The column chunk is a 17 byte page header and an 8 byte page at offset 4.
+	footer := golden(t, `
+		15 02 // version: 1
+		19 2c // schema: list of 2 structs
+		   48 06 736368656d61 // name: "schema"
+		   15 02              // num_children: 1
+		   00
+		   15 04    // type: INT64
+		   25 00    // repetition_type: REQUIRED
+		   18 01 6e // name: "n"
+		   00
+		16 02 // num_rows: 1
+		19 1c // row_groups: list of 1 struct
+		   19 1c // columns: list of 1 struct
+		      26 08 // file_offset: 4
+		      1c    // meta_data
+		         15 04       // type: INT64
+		         19 25 00 06 // encodings: PLAIN, RLE
+		         19 18 01 6e // path_in_schema: "n"
+		         15 00       // codec: UNCOMPRESSED
+		         16 02       // num_values: 1
+		         16 32       // total_uncompressed_size: 25
+		         16 32       // total_compressed_size: 25
+		         26 08       // data_page_offset: 4
+		         00
+		      00
+		   16 32 // total_byte_size: 25
+		   16 02 // num_rows: 1
+		   00
+		28 0a 66616c636f6e782d676f // created_by: "falconx-go"
+		00`)
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	want := append(append(footer, length[:]...), magic...)
+
+	file := out.Bytes()
+	if len(file) != 4+25+len(want) {
+		t.Fatalf("file is %d bytes, want %d", len(file), 4+25+len(want))
+	}
+	if got := file[4+25:]; !bytes.Equal(got, want) {
+		t.Errorf("footer = % x, want % x", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	at := time.Date(2021, 3, 1, 12, 30, 0, 123456000, time.FixedZone("EST", -5*3600))
+	columns := []Column{
+		{Name: "pair", Type: String, Optional: true},
+		{Name: "filled", Type: Boolean},
+		{Name: "hedged", Type: Boolean, Optional: true},
+		{Name: "price", Type: Double, Optional: true},
+		{Name: "count", Type: Int64},
+		{Name: "executed", Type: Timestamp, Optional: true},
+	}
+	groups := [][][]interface{}{
+		{
+			{"BTC/USD", true, nil, 100.5, int64(1), at},
+			{nil, false, true, nil, 2, time.Time{}},
+			{"ETH/USD", true, false, 2.25, int64(-3), nil},
+		},
+		{},
+		{
+			{"", false, nil, nil, int64(4), at.Add(time.Second)},
+		},
+	}
+	// Rows are read back with int64 counts, UTC timestamps and nulls for zero times.
+	want := [][]interface{}{
+		{"BTC/USD", true, nil, 100.5, int64(1), at.UTC()},
+		{nil, false, true, nil, int64(2), nil},
+		{"ETH/USD", true, false, 2.25, int64(-3), nil},
+		{"", false, nil, nil, int64(4), at.Add(time.Second).UTC()},
+	}
+
+	var out bytes.Buffer
+	writer, err := NewWriter(&out, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rows := range groups {
+		if err := writer.WriteRowGroup(rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteRowGroup(groups[0]); err != ErrClosed {
+		t.Errorf("WriteRowGroup after Close = %v, want ErrClosed", err)
+	}
+
+	got, err := readFile(out.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read back\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestWriteRowGroupErrors(t *testing.T) {
+	writer, err := NewWriter(&bytes.Buffer{}, []Column{{Name: "n", Type: Int64}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rows := range [][][]interface{}{
+		{{nil}},
+		{{"1"}},
+		{{int64(1), int64(2)}},
+	} {
+		if err := writer.WriteRowGroup(rows); err == nil {
+			t.Errorf("WriteRowGroup(%v) succeeded", rows)
+		}
+	}
+}
+
+// readFile reads back the files Writer produces: one data page per column chunk, PLAIN
+// values and RLE definition levels.
+func readFile(file []byte) ([][]interface{}, error) {
+	if len(file) < 12 || string(file[:4]) != magic || string(file[len(file)-4:]) != magic {
+		return nil, errors.New("missing magic")
+	}
+	length := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer := file[len(file)-8-length : len(file)-8]
+	meta, rest, err := readStruct(footer)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing bytes after footer")
+	}
+
+	schema := meta[2].([]interface{})[1:]
+	var rows [][]interface{}
+	for _, group := range meta[4].([]interface{}) {
+		group := group.(map[int16]interface{})
+		groupRows := make([][]interface{}, group[3].(int64))
+		for i := range groupRows {
+			groupRows[i] = make([]interface{}, len(schema))
+		}
+		for c, chunk := range group[1].([]interface{}) {
+			element := schema[c].(map[int16]interface{})
+			columnMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			header, page, err := readStruct(file[columnMeta[9].(int64):])
+			if err != nil {
+				return nil, err
+			}
+			page = page[:header[3].(int64)]
+			values, err := readPage(element, page, len(groupRows))
+			if err != nil {
+				return nil, err
+			}
+			for r, value := range values {
+				groupRows[r][c] = value
+			}
+		}
+		rows = append(rows, groupRows...)
+	}
+	return rows, nil
+}
+
+func readPage(element map[int16]interface{}, page []byte, count int) ([]interface{}, error) {
+	defined := make([]bool, count)
+	for i := range defined {
+		defined[i] = true
+	}
+	if element[3].(int64) == repetitionOptional {
+		length := binary.LittleEndian.Uint32(page)
+		levels := page[4 : 4+length]
+		page = page[4+length:]
+		for i := 0; len(levels) > 0; {
+			header, n := binary.Uvarint(levels)
+			if header&1 != 0 {
+				return nil, errors.New("bit-packed levels")
+			}
+			for run := 0; run < int(header>>1); run++ {
+				defined[i] = levels[n] == 1
+				i++
+			}
+			levels = levels[n+1:]
+		}
+	}
+
+	values := make([]interface{}, count)
+	bit := 0
+	for i := range values {
+		if !defined[i] {
+			continue
+		}
+		switch element[1].(int64) {
+		case physicalByteArray:
+			length := binary.LittleEndian.Uint32(page)
+			values[i] = string(page[4 : 4+length])
+			page = page[4+length:]
+		case physicalDouble:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case physicalInt64:
+			n := int64(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+			if element[6] == int64(convertedTimestampMicros) {
+				values[i] = time.Unix(0, n*int64(time.Microsecond)).UTC()
+			} else {
+				values[i] = n
+			}
+		case physicalBoolean:
+			values[i] = page[bit/8]&(1<<uint(bit%8)) != 0
+			bit++
+		default:
+			return nil, fmt.Errorf("unexpected physical type %v", element[1])
+		}
+	}
+	return values, nil
+}
+
+// readStruct decodes a Thrift compact protocol struct into its fields by id. Integers
+// decode to int64, binaries to string, lists to []interface{} and structs to maps.
+func readStruct(data []byte) (map[int16]interface{}, []byte, error) {
+	fields := make(map[int16]interface{})
+	var id int16
+	for {
+		if len(data) == 0 {
+			return nil, nil, errors.New("truncated struct")
+		}
+		header := data[0]
+		data = data[1:]
+		if header == 0 {
+			return fields, data, nil
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			n, size := binary.Varint(data)
+			id, data = int16(n), data[size:]
+		}
+		var value interface{}
+		var err error
+		value, data, err = readValue(header&0x0f, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields[id] = value
+	}
+}
+
+func readValue(kind byte, data []byte) (interface{}, []byte, error) {
+	switch kind {
+	case compactI32, compactI64:
+		n, size := binary.Varint(data)
+		return n, data[size:], nil
+	case compactBinary:
+		length, size := binary.Uvarint(data)
+		data = data[size:]
+		return string(data[:length]), data[length:], nil
+	case compactList:
+		header := data[0]
+		data = data[1:]
+		count := uint64(header >> 4)
+		if count == 15 {
+			var size int
+			count, size = binary.Uvarint(data)
+			data = data[size:]
+		}
+		list := make([]interface{}, count)
+		for i := range list {
+			var err error
+			list[i], data, err = readValue(header&0x0f, data)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return list, data, nil
+	case compactStruct:
+		return readStruct(data)
+	}
+	return nil, nil, fmt.Errorf("unexpected compact type %d", kind)
+}
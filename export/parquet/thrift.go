@@ -0,0 +1,99 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type identifiers.
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes the subset of the Thrift compact protocol used by Parquet
+// metadata: structs, lists, i32, i64 and binary fields.
+type compactWriter struct {
+	buf    bytes.Buffer
+	lastID int16
+	stack  []int16
+}
+
+func (writer *compactWriter) fieldHeader(id int16, kind byte) {
+	delta := id - writer.lastID
+	if delta > 0 && delta <= 15 {
+		writer.buf.WriteByte(byte(delta)<<4 | kind)
+	} else {
+		writer.buf.WriteByte(kind)
+		writer.varint(int64(id))
+	}
+	writer.lastID = id
+}
+
+func (writer *compactWriter) uvarint(value uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], value)
+	writer.buf.Write(scratch[:n])
+}
+
+// varint writes a zigzag encoded integer.
+func (writer *compactWriter) varint(value int64) {
+	writer.uvarint(uint64((value << 1) ^ (value >> 63)))
+}
+
+func (writer *compactWriter) i32(id int16, value int32) {
+	writer.fieldHeader(id, compactI32)
+	writer.varint(int64(value))
+}
+
+func (writer *compactWriter) i64(id int16, value int64) {
+	writer.fieldHeader(id, compactI64)
+	writer.varint(value)
+}
+
+func (writer *compactWriter) binary(id int16, value string) {
+	writer.fieldHeader(id, compactBinary)
+	writer.uvarint(uint64(len(value)))
+	writer.buf.WriteString(value)
+}
+
+// structField starts a nested struct field; close it with end.
+func (writer *compactWriter) structField(id int16) {
+	writer.fieldHeader(id, compactStruct)
+	writer.begin()
+}
+
+// begin starts a struct that is not preceded by a field header: the top-level struct or
+// a list element.
+func (writer *compactWriter) begin() {
+	writer.stack = append(writer.stack, writer.lastID)
+	writer.lastID = 0
+}
+
+func (writer *compactWriter) end() {
+	writer.buf.WriteByte(0)
+	writer.lastID = writer.stack[len(writer.stack)-1]
+	writer.stack = writer.stack[:len(writer.stack)-1]
+}
+
+func (writer *compactWriter) list(id int16, elemKind byte, size int) {
+	writer.fieldHeader(id, compactList)
+	if size < 15 {
+		writer.buf.WriteByte(byte(size)<<4 | elemKind)
+		return
+	}
+	writer.buf.WriteByte(0xf0 | elemKind)
+	writer.uvarint(uint64(size))
+}
+
+func (writer *compactWriter) i32Elem(value int32) {
+	writer.varint(int64(value))
+}
+
+func (writer *compactWriter) binaryElem(value string) {
+	writer.uvarint(uint64(len(value)))
+	writer.buf.WriteString(value)
+}
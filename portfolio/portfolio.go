@@ -0,0 +1,375 @@
+// Package portfolio builds positions and P&L from FalconX fills.
+//
+// A Book ingests executed quotes and orders, keeps a net position per token and per pair,
+// and computes realized P&L with FIFO, LIFO or average cost matching. Unrealized P&L is
+// marked to the latest mid, typically taken from the price ladder stream. P&L is expressed
+// in the pair's quote token; fees are reported separately in USD.
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// CostMethod selects which open lots a closing fill is matched against.
+type CostMethod string
+
+const (
+	// FIFO closes the oldest open lots first.
+	FIFO CostMethod = "fifo"
+	// LIFO closes the most recent open lots first.
+	LIFO CostMethod = "lifo"
+	// AverageCost keeps a single lot at the volume weighted entry price.
+	AverageCost CostMethod = "average"
+)
+
+// quantityEpsilon absorbs rounding left over when lots are closed.
+const quantityEpsilon = 1e-12
+
+// ErrNotFilled is returned when a quote or order that was not filled is added.
+var ErrNotFilled = errors.New("portfolio: response is not filled")
+
+// Fill is a single execution in base token quantity.
+type Fill struct {
+	FxQuoteId string
+	TokenPair clients.TokenPair
+	// Side is buy or sell.
+	Side string
+	// Quantity is in base token.
+	Quantity float64
+	// Price is in quote token per base token.
+	Price  float64
+	FeeUSD float64
+	Time   time.Time
+}
+
+// FillFromQuote converts an executed quote, e.g. from GetExecutedQuotes, including its
+// FeeUSD. Quantities requested in quote token are converted to base token at the
+// executed price.
+func FillFromQuote(quote clients.QuoteResponse) (Fill, error) {
+	if !quote.IsFilled {
+		return Fill{}, fmt.Errorf("%w: %s", ErrNotFilled, quote.FxQuoteId)
+	}
+	side := quote.SideExecuted.Or(quote.SideRequested)
+	return newFill(quote.FxQuoteId, quote.TokenPair, side, quote.Quantity, quote.ExecutedPrice(), quote.FeeUSD, quote.ExecutionTime.Time)
+}
+
+// FillFromOrder converts a filled PlaceOrder response, including its FeeUSD.
+func FillFromOrder(order clients.OrderResponse) (Fill, error) {
+	if !order.IsFilled {
+		return Fill{}, fmt.Errorf("%w: %s", ErrNotFilled, order.FxQuoteId)
+	}
+	side := order.SideExecuted.Or(order.SideRequested)
+	return newFill(order.FxQuoteId, order.TokenPair, side, order.Quantity, order.ExecutedPrice(), order.FeeUSD, order.ExecutionTime.Time)
+}
+
+func newFill(id string, pair clients.TokenPair, side string, quantity clients.Quantity, price, fee float64, at time.Time) (Fill, error) {
+	if side != "buy" && side != "sell" {
+		return Fill{}, fmt.Errorf("portfolio: fill %s has invalid side %q", id, side)
+	}
+	if price <= 0 {
+		return Fill{}, fmt.Errorf("portfolio: fill %s has no executed price", id)
+	}
+
+	base := quantity.Value
+	switch quantity.Token {
+	case "", pair.BaseToken:
+	case pair.QuoteToken:
+		base = quantity.Value / price
+	default:
+		return Fill{}, fmt.Errorf("portfolio: fill %s quantity token %s not in pair %s/%s",
+			id, quantity.Token, pair.BaseToken, pair.QuoteToken)
+	}
+
+	return Fill{
+		FxQuoteId: id,
+		TokenPair: pair,
+		Side:      side,
+		Quantity:  base,
+		Price:     price,
+		FeeUSD:    fee,
+		Time:      at,
+	}, nil
+}
+
+// PnL is the position and P&L of one pair. Amounts are in the pair's quote token, except
+// FeesUSD.
+type PnL struct {
+	TokenPair clients.TokenPair
+	// Position is the net base token position; negative when short.
+	Position float64
+	// AverageCost is the average entry price of the open position.
+	AverageCost float64
+	Realized    float64
+	// Unrealized is zero until the pair has been marked.
+	Unrealized float64
+	Mark       float64
+	MarkTime   time.Time
+	FeesUSD    float64
+}
+
+// Total returns realized plus unrealized P&L, before fees.
+func (pnl PnL) Total() float64 {
+	return pnl.Realized + pnl.Unrealized
+}
+
+// Book tracks positions and P&L. It is safe for concurrent use.
+type Book struct {
+	method CostMethod
+
+	mu        sync.Mutex
+	seen      map[string]bool
+	pairs     map[clients.TokenPair]*position
+	positions map[string]float64
+	feesUSD   float64
+}
+
+type lot struct {
+	// quantity is signed: positive for long lots, negative for short ones.
+	quantity float64
+	price    float64
+}
+
+type position struct {
+	lots     []lot
+	realized float64
+	mark     float64
+	markTime time.Time
+	feesUSD  float64
+}
+
+// NewBook creates an empty book using method to match closing fills. An empty method
+// defaults to FIFO.
+func NewBook(method CostMethod) (*Book, error) {
+	switch method {
+	case "":
+		method = FIFO
+	case FIFO, LIFO, AverageCost:
+	default:
+		return nil, fmt.Errorf("portfolio: unknown cost method %q", method)
+	}
+
+	return &Book{
+		method:    method,
+		seen:      make(map[string]bool),
+		pairs:     make(map[clients.TokenPair]*position),
+		positions: make(map[string]float64),
+	}, nil
+}
+
+// Add applies a fill. Fills are deduplicated by FxQuoteId, so overlapping
+// GetExecutedQuotes windows can be added safely; Add reports whether the fill was new.
+func (book *Book) Add(fill Fill) (bool, error) {
+	if fill.Side != "buy" && fill.Side != "sell" {
+		return false, fmt.Errorf("portfolio: fill %s has invalid side %q", fill.FxQuoteId, fill.Side)
+	}
+	if fill.Quantity <= 0 || fill.Price <= 0 {
+		return false, fmt.Errorf("portfolio: fill %s needs a positive quantity and price", fill.FxQuoteId)
+	}
+
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	if fill.FxQuoteId != "" {
+		if book.seen[fill.FxQuoteId] {
+			return false, nil
+		}
+		book.seen[fill.FxQuoteId] = true
+	}
+
+	signed := fill.Quantity
+	if fill.Side == "sell" {
+		signed = -signed
+	}
+	book.positions[fill.TokenPair.BaseToken] += signed
+	book.positions[fill.TokenPair.QuoteToken] -= signed * fill.Price
+	book.feesUSD += fill.FeeUSD
+
+	pos := book.pairs[fill.TokenPair]
+	if pos == nil {
+		pos = &position{}
+		book.pairs[fill.TokenPair] = pos
+	}
+	pos.feesUSD += fill.FeeUSD
+	book.apply(pos, signed, fill.Price)
+	return true, nil
+}
+
+// AddQuote applies an executed quote. See FillFromQuote.
+func (book *Book) AddQuote(quote clients.QuoteResponse) (bool, error) {
+	fill, err := FillFromQuote(quote)
+	if err != nil {
+		return false, err
+	}
+	return book.Add(fill)
+}
+
+// AddOrder applies a filled order. See FillFromOrder.
+func (book *Book) AddOrder(order clients.OrderResponse) (bool, error) {
+	fill, err := FillFromOrder(order)
+	if err != nil {
+		return false, err
+	}
+	return book.Add(fill)
+}
+
+// AddQuotes applies every filled quote, e.g. the result of GetExecutedQuotes, and skips
+// the rest. It returns the number of new fills.
+func (book *Book) AddQuotes(quotes []clients.QuoteResponse) (int, error) {
+	added := 0
+	for _, quote := range quotes {
+		if !quote.IsFilled {
+			continue
+		}
+		isNew, err := book.AddQuote(quote)
+		if err != nil {
+			return added, err
+		}
+		if isNew {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// Mark sets the price unrealized P&L of pair is computed at.
+func (book *Book) Mark(pair clients.TokenPair, mid float64, at time.Time) {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	pos := book.pairs[pair]
+	if pos == nil {
+		pos = &position{}
+		book.pairs[pair] = pos
+	}
+	pos.mark = mid
+	pos.markTime = at
+}
+
+// MarkLadder marks the ladder's pair to its mid. It can be passed directly to
+// SocketClient.OnPriceLadder.
+func (book *Book) MarkLadder(ladder clients.PriceLadder) {
+	if mid, ok := ladder.Mid(); ok {
+		book.Mark(ladder.TokenPair, mid, ladder.QuoteTime)
+	}
+}
+
+// Positions returns the net position per token across all pairs.
+func (book *Book) Positions() map[string]float64 {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	result := make(map[string]float64, len(book.positions))
+	for token, quantity := range book.positions {
+		result[token] = quantity
+	}
+	return result
+}
+
+// PnL returns the position and P&L of pair.
+func (book *Book) PnL(pair clients.TokenPair) PnL {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	pos := book.pairs[pair]
+	if pos == nil {
+		return PnL{TokenPair: pair}
+	}
+	return pos.pnl(pair)
+}
+
+// Summary returns the P&L of every pair that has been traded or marked, sorted by pair.
+func (book *Book) Summary() []PnL {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	result := make([]PnL, 0, len(book.pairs))
+	for pair, pos := range book.pairs {
+		result = append(result, pos.pnl(pair))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].TokenPair, result[j].TokenPair
+		if a.BaseToken != b.BaseToken {
+			return a.BaseToken < b.BaseToken
+		}
+		return a.QuoteToken < b.QuoteToken
+	})
+	return result
+}
+
+// FeesUSD returns the fees paid across all fills.
+func (book *Book) FeesUSD() float64 {
+	book.mu.Lock()
+	defer book.mu.Unlock()
+	return book.feesUSD
+}
+
+// apply matches signed against open lots of the opposite sign and opens a lot with
+// whatever is left. Callers must hold book.mu.
+func (book *Book) apply(pos *position, signed, price float64) {
+	for math.Abs(signed) > quantityEpsilon && len(pos.lots) > 0 {
+		i := 0
+		if book.method == LIFO {
+			i = len(pos.lots) - 1
+		}
+		open := &pos.lots[i]
+		if (open.quantity > 0) == (signed > 0) {
+			break
+		}
+
+		matched := math.Min(math.Abs(signed), math.Abs(open.quantity))
+		if open.quantity > 0 {
+			pos.realized += matched * (price - open.price)
+			open.quantity -= matched
+			signed += matched
+		} else {
+			pos.realized += matched * (open.price - price)
+			open.quantity += matched
+			signed -= matched
+		}
+		if math.Abs(open.quantity) <= quantityEpsilon {
+			pos.lots = append(pos.lots[:i], pos.lots[i+1:]...)
+		}
+	}
+
+	if math.Abs(signed) <= quantityEpsilon {
+		return
+	}
+	if book.method == AverageCost && len(pos.lots) == 1 {
+		open := &pos.lots[0]
+		total := open.quantity + signed
+		open.price = (open.quantity*open.price + signed*price) / total
+		open.quantity = total
+		return
+	}
+	pos.lots = append(pos.lots, lot{quantity: signed, price: price})
+}
+
+func (pos *position) pnl(pair clients.TokenPair) PnL {
+	result := PnL{
+		TokenPair: pair,
+		Realized:  pos.realized,
+		Mark:      pos.mark,
+		MarkTime:  pos.markTime,
+		FeesUSD:   pos.feesUSD,
+	}
+
+	var cost float64
+	for _, open := range pos.lots {
+		result.Position += open.quantity
+		cost += open.quantity * open.price
+		if pos.mark > 0 {
+			result.Unrealized += open.quantity * (pos.mark - open.price)
+		}
+	}
+	if math.Abs(result.Position) > quantityEpsilon {
+		result.AverageCost = cost / result.Position
+	}
+	return result
+}
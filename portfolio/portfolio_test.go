@@ -0,0 +1,103 @@
+package portfolio_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/portfolio"
+)
+
+var (
+	btcUSD = clients.TokenPair{BaseToken: "BTC", QuoteToken: "USD"}
+	ethBTC = clients.TokenPair{BaseToken: "ETH", QuoteToken: "BTC"}
+)
+
+func filledQuote(id string, pair clients.TokenPair, side string, quantity, price, fee float64) clients.QuoteResponse {
+	quote := clients.QuoteResponse{
+		FxQuoteId:     id,
+		TokenPair:     pair,
+		Quantity:      clients.Quantity{Token: pair.BaseToken, Value: quantity},
+		SideRequested: side,
+		SideExecuted:  clients.NewNullSide(side),
+		IsFilled:      true,
+		ExecutionTime: clients.NewNullTime(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)),
+		FeeUSD:        fee,
+	}
+	if side == "buy" {
+		quote.BuyPrice = clients.NewNullFloat64(price)
+	} else {
+		quote.SellPrice = clients.NewNullFloat64(price)
+	}
+	return quote
+}
+
+func TestFillFromQuoteKeepsFee(t *testing.T) {
+	fill, err := portfolio.FillFromQuote(filledQuote("q1", ethBTC, "buy", 10, 0.05, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fill.FeeUSD != 3 {
+		t.Errorf("FeeUSD = %v, want 3", fill.FeeUSD)
+	}
+}
+
+func TestBookFeesAcrossPairs(t *testing.T) {
+	book, err := portfolio.NewBook(portfolio.FIFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := book.AddQuotes([]clients.QuoteResponse{
+		filledQuote("q1", btcUSD, "buy", 1, 100, 2),
+		filledQuote("q2", ethBTC, "buy", 10, 0.05, 3),
+		filledQuote("q1", btcUSD, "buy", 1, 100, 2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 2 {
+		t.Errorf("added %d fills, want 2 after deduplication", added)
+	}
+	if fees := book.FeesUSD(); fees != 5 {
+		t.Errorf("FeesUSD = %v, want 5", fees)
+	}
+	if fees := book.PnL(ethBTC).FeesUSD; fees != 3 {
+		t.Errorf("ETH/BTC FeesUSD = %v, want 3", fees)
+	}
+}
+
+func TestRealizedPnLByCostMethod(t *testing.T) {
+	quotes := []clients.QuoteResponse{
+		filledQuote("q1", btcUSD, "buy", 1, 100, 0),
+		filledQuote("q2", btcUSD, "buy", 1, 200, 0),
+		filledQuote("q3", btcUSD, "sell", 1, 250, 0),
+	}
+	for _, test := range []struct {
+		method      portfolio.CostMethod
+		realized    float64
+		averageCost float64
+	}{
+		{portfolio.FIFO, 150, 200},
+		{portfolio.LIFO, 50, 100},
+		{portfolio.AverageCost, 100, 150},
+	} {
+		book, err := portfolio.NewBook(test.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := book.AddQuotes(quotes); err != nil {
+			t.Fatal(err)
+		}
+		book.Mark(btcUSD, 300, time.Time{})
+
+		pnl := book.PnL(btcUSD)
+		if math.Abs(pnl.Realized-test.realized) > 1e-9 || math.Abs(pnl.AverageCost-test.averageCost) > 1e-9 {
+			t.Errorf("%s: realized %v at average cost %v, want %v at %v",
+				test.method, pnl.Realized, pnl.AverageCost, test.realized, test.averageCost)
+		}
+		if want := 300 - test.averageCost; math.Abs(pnl.Unrealized-want) > 1e-9 {
+			t.Errorf("%s: unrealized %v, want %v", test.method, pnl.Unrealized, want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+// Package reconcile checks that locally derived balances agree with FalconX.
+//
+// A reconciliation starts from a balance snapshot, applies the fills returned by
+// GetExecutedQuotes and the deposits and withdrawals returned by GetTransfers over a
+// period, and compares the result with GetBalances and optionally GetTotalBalances.
+// FalconX reports fees in USD only, so the fee of every fill is debited from the USD
+// balance whatever the pair. Every token whose difference exceeds the tolerance is
+// reported as a Break together with the transactions that moved it.
+package reconcile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/portfolio"
+)
+
+const defaultTolerance = 1e-8
+
+// Source names the FalconX call a balance was compared against.
+type Source string
+
+const (
+	SourceBalances      Source = "balances"
+	SourceTotalBalances Source = "total_balances"
+)
+
+// Kind is the type of a Transaction.
+type Kind string
+
+const (
+	KindFill       Kind = "fill"
+	KindFee        Kind = "fee"
+	KindDeposit    Kind = "deposit"
+	KindWithdrawal Kind = "withdrawal"
+)
+
+// Snapshot is the set of balances known to be correct at a point in time.
+type Snapshot struct {
+	Time     time.Time
+	Balances map[string]float64
+}
+
+// SnapshotFromBalances builds a snapshot from a GetBalances result, summing platforms.
+func SnapshotFromBalances(balances []clients.Balance, at time.Time) Snapshot {
+	return Snapshot{Time: at, Balances: sumBalances(balances)}
+}
+
+// Transaction is one balance movement of a single token.
+type Transaction struct {
+	Kind Kind
+	// ID is the fx_quote_id for fills and fees, and empty for transfers.
+	ID    string
+	Token string
+	// Amount is signed: positive when the balance increases.
+	Amount float64
+	Time   time.Time
+}
+
+// Break is a token whose expected and reported balances differ beyond the tolerance.
+type Break struct {
+	Token  string
+	Source Source
+	// Expected is the snapshot balance plus every transaction in the period.
+	Expected float64
+	Actual   float64
+	// Difference is Actual minus Expected.
+	Difference   float64
+	Transactions []Transaction
+}
+
+func (brk Break) String() string {
+	return fmt.Sprintf("%s (%s): expected %v, actual %v, difference %v over %d transactions",
+		brk.Token, brk.Source, brk.Expected, brk.Actual, brk.Difference, len(brk.Transactions))
+}
+
+// Report is the outcome of a reconciliation.
+type Report struct {
+	Start        time.Time
+	End          time.Time
+	Opening      map[string]float64
+	Expected     map[string]float64
+	Balances     map[string]float64
+	Transactions []Transaction
+	// TotalBalances is nil unless Config.CompareTotalBalances is set.
+	TotalBalances map[string]float64
+	Breaks        []Break
+}
+
+// OK reports whether no breaks were found.
+func (report Report) OK() bool {
+	return len(report.Breaks) == 0
+}
+
+// Config configures a Reconciler.
+type Config struct {
+	// Tolerance is the largest absolute difference that is not a break. Defaults to 1e-8.
+	Tolerance float64
+	// Tolerances overrides Tolerance per token.
+	Tolerances map[string]float64
+	// CompareTotalBalances also compares against GetTotalBalances. Only enable it when
+	// the snapshot covers every platform, since totals include non-API balances.
+	CompareTotalBalances bool
+	// IncludeTransfer decides which transfers move balances. By default every transfer
+	// is included except those whose status is failed, cancelled or rejected.
+	IncludeTransfer func(clients.Transfer) bool
+}
+
+// Reconciler fetches fills, transfers and balances and reconciles them.
+type Reconciler struct {
+	client clients.Account
+	config Config
+}
+
+// New creates a Reconciler on top of client.
+func New(client clients.Account, config Config) *Reconciler {
+	if config.Tolerance <= 0 {
+		config.Tolerance = defaultTolerance
+	}
+	if config.IncludeTransfer == nil {
+		config.IncludeTransfer = settledTransfer
+	}
+	return &Reconciler{client: client, config: config}
+}
+
+// Run reconciles opening against the current balances, applying every fill and transfer
+// between opening.Time and end. end should be the time the balances are read; it
+// defaults to now when zero.
+func (reconciler *Reconciler) Run(opening Snapshot, end time.Time) (Report, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	quotes, err := reconciler.client.GetExecutedQuotes(opening.Time, end)
+	if err != nil {
+		return Report{}, fmt.Errorf("reconcile: fetching executed quotes: %w", err)
+	}
+	transfers, err := reconciler.client.GetTransfers(opening.Time, end)
+	if err != nil {
+		return Report{}, fmt.Errorf("reconcile: fetching transfers: %w", err)
+	}
+	balances, err := reconciler.client.GetBalances()
+	if err != nil {
+		return Report{}, fmt.Errorf("reconcile: fetching balances: %w", err)
+	}
+	var totals []clients.TotalBalance
+	if reconciler.config.CompareTotalBalances {
+		totals, err = reconciler.client.GetTotalBalances()
+		if err != nil {
+			return Report{}, fmt.Errorf("reconcile: fetching total balances: %w", err)
+		}
+	}
+
+	return reconciler.Reconcile(opening, end, quotes, transfers, balances, totals)
+}
+
+// Reconcile runs the comparison on data that was already fetched, e.g. from an export.
+// totals may be nil to skip the GetTotalBalances comparison.
+func (reconciler *Reconciler) Reconcile(opening Snapshot, end time.Time, quotes []clients.QuoteResponse,
+	transfers []clients.Transfer, balances []clients.Balance, totals []clients.TotalBalance) (Report, error) {
+	transactions, err := reconciler.transactions(opening.Time, end, quotes, transfers)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Start:        opening.Time,
+		End:          end,
+		Opening:      copyBalances(opening.Balances),
+		Expected:     copyBalances(opening.Balances),
+		Balances:     sumBalances(balances),
+		Transactions: transactions,
+	}
+	byToken := make(map[string][]Transaction)
+	for _, transaction := range transactions {
+		report.Expected[transaction.Token] += transaction.Amount
+		byToken[transaction.Token] = append(byToken[transaction.Token], transaction)
+	}
+
+	report.Breaks = reconciler.compare(SourceBalances, report.Expected, report.Balances, byToken)
+	if totals != nil {
+		report.TotalBalances = make(map[string]float64, len(totals))
+		for _, total := range totals {
+			report.TotalBalances[total.Token] += total.TotalBalance
+		}
+		report.Breaks = append(report.Breaks,
+			reconciler.compare(SourceTotalBalances, report.Expected, report.TotalBalances, byToken)...)
+	}
+	return report, nil
+}
+
+// transactions turns fills and transfers in (start, end] into per-token movements,
+// oldest first. Fills are deduplicated by fx_quote_id.
+func (reconciler *Reconciler) transactions(start, end time.Time, quotes []clients.QuoteResponse,
+	transfers []clients.Transfer) ([]Transaction, error) {
+	var result []Transaction
+	inPeriod := func(at time.Time) bool { return at.After(start) && !at.After(end) }
+
+	seen := make(map[string]bool)
+	for _, quote := range quotes {
+		if !quote.IsFilled || seen[quote.FxQuoteId] || !inPeriod(quote.ExecutionTime.Time) {
+			continue
+		}
+		seen[quote.FxQuoteId] = true
+
+		fill, err := portfolio.FillFromQuote(quote)
+		if err != nil {
+			return nil, fmt.Errorf("reconcile: %w", err)
+		}
+		base, quoteAmount := fill.Quantity, fill.Quantity*fill.Price
+		if fill.Side == "sell" {
+			base, quoteAmount = -base, -quoteAmount
+		}
+		result = append(result,
+			Transaction{Kind: KindFill, ID: fill.FxQuoteId, Token: fill.TokenPair.BaseToken, Amount: base, Time: fill.Time},
+			Transaction{Kind: KindFill, ID: fill.FxQuoteId, Token: fill.TokenPair.QuoteToken, Amount: -quoteAmount, Time: fill.Time},
+		)
+		if fill.FeeUSD != 0 {
+			result = append(result,
+				Transaction{Kind: KindFee, ID: fill.FxQuoteId, Token: "USD", Amount: -fill.FeeUSD, Time: fill.Time})
+		}
+	}
+
+	for _, transfer := range transfers {
+		if !inPeriod(transfer.CreateTime) || !reconciler.config.IncludeTransfer(transfer) {
+			continue
+		}
+		transaction := Transaction{Token: transfer.Token, Amount: math.Abs(transfer.Quantity), Time: transfer.CreateTime}
+		switch strings.ToLower(transfer.Type) {
+		case "deposit":
+			transaction.Kind = KindDeposit
+		case "withdrawal":
+			transaction.Kind = KindWithdrawal
+			transaction.Amount = -transaction.Amount
+		default:
+			return nil, fmt.Errorf("reconcile: unknown transfer type %q for %s", transfer.Type, transfer.Token)
+		}
+		result = append(result, transaction)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result, nil
+}
+
+func (reconciler *Reconciler) compare(source Source, expected, actual map[string]float64,
+	byToken map[string][]Transaction) []Break {
+	tokens := make(map[string]bool)
+	for token := range expected {
+		tokens[token] = true
+	}
+	for token := range actual {
+		tokens[token] = true
+	}
+
+	var breaks []Break
+	for token := range tokens {
+		difference := actual[token] - expected[token]
+		if math.Abs(difference) <= reconciler.tolerance(token) {
+			continue
+		}
+		breaks = append(breaks, Break{
+			Token:        token,
+			Source:       source,
+			Expected:     expected[token],
+			Actual:       actual[token],
+			Difference:   difference,
+			Transactions: byToken[token],
+		})
+	}
+	sort.Slice(breaks, func(i, j int) bool { return breaks[i].Token < breaks[j].Token })
+	return breaks
+}
+
+func (reconciler *Reconciler) tolerance(token string) float64 {
+	if tolerance, ok := reconciler.config.Tolerances[token]; ok {
+		return tolerance
+	}
+	return reconciler.config.Tolerance
+}
+
+func settledTransfer(transfer clients.Transfer) bool {
+	switch strings.ToLower(transfer.Status) {
+	case "failed", "cancelled", "canceled", "rejected":
+		return false
+	}
+	return true
+}
+
+func sumBalances(balances []clients.Balance) map[string]float64 {
+	result := make(map[string]float64, len(balances))
+	for _, balance := range balances {
+		result[balance.Token] += balance.Balance
+	}
+	return result
+}
+
+func copyBalances(balances map[string]float64) map[string]float64 {
+	result := make(map[string]float64, len(balances))
+	for token, balance := range balances {
+		result[token] = balance
+	}
+	return result
+}
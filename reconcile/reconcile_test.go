@@ -0,0 +1,90 @@
+package reconcile_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/clients/mocks"
+	"github.com/falconxio/falconx-go/reconcile"
+)
+
+var opening = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// filledQuote is a filled buy of quantity base token at price, executed an hour after
+// the opening snapshot.
+func filledQuote(id string, pair clients.TokenPair, quantity, price, fee float64) clients.QuoteResponse {
+	return clients.QuoteResponse{
+		FxQuoteId:     id,
+		TokenPair:     pair,
+		Quantity:      clients.Quantity{Token: pair.BaseToken, Value: quantity},
+		SideRequested: "buy",
+		SideExecuted:  clients.NewNullSide("buy"),
+		BuyPrice:      clients.NewNullFloat64(price),
+		IsFilled:      true,
+		ExecutionTime: clients.NewNullTime(opening.Add(time.Hour)),
+		FeeUSD:        fee,
+	}
+}
+
+func TestFeesAreDebitedFromUSDForEveryPair(t *testing.T) {
+	client := &mocks.AccountMock{
+		GetExecutedQuotesFunc: func(tStart, tEnd time.Time) ([]clients.QuoteResponse, error) {
+			return []clients.QuoteResponse{
+				filledQuote("usd", clients.TokenPair{BaseToken: "BTC", QuoteToken: "USD"}, 1, 100, 2),
+				filledQuote("btc", clients.TokenPair{BaseToken: "ETH", QuoteToken: "BTC"}, 10, 0.05, 3),
+			}, nil
+		},
+		GetTransfersFunc: func(tStart, tEnd time.Time) ([]clients.Transfer, error) {
+			return nil, nil
+		},
+		GetBalancesFunc: func() ([]clients.Balance, error) {
+			return []clients.Balance{
+				{Token: "USD", Balance: 1000 - 100 - 2 - 3},
+				{Token: "BTC", Balance: 1 + 1 - 0.5},
+				{Token: "ETH", Balance: 10},
+			}, nil
+		},
+	}
+
+	snapshot := reconcile.Snapshot{Time: opening, Balances: map[string]float64{"USD": 1000, "BTC": 1}}
+	report, err := reconcile.New(client, reconcile.Config{Tolerance: 1e-9}).Run(snapshot, opening.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.OK() {
+		t.Fatalf("breaks: %v", report.Breaks)
+	}
+
+	var fees float64
+	for _, transaction := range report.Transactions {
+		if transaction.Kind != reconcile.KindFee {
+			continue
+		}
+		if transaction.Token != "USD" {
+			t.Errorf("fee of %s booked in %s, want USD", transaction.ID, transaction.Token)
+		}
+		fees += transaction.Amount
+	}
+	if math.Abs(fees+5) > 1e-9 {
+		t.Errorf("fees = %v, want -5", fees)
+	}
+}
+
+func TestUnbookedFeeIsABreak(t *testing.T) {
+	quotes := []clients.QuoteResponse{
+		filledQuote("btc", clients.TokenPair{BaseToken: "ETH", QuoteToken: "BTC"}, 10, 0.05, 3),
+	}
+	balances := []clients.Balance{{Token: "USD", Balance: 1000}, {Token: "BTC", Balance: 0.5}, {Token: "ETH", Balance: 10}}
+	snapshot := reconcile.Snapshot{Time: opening, Balances: map[string]float64{"USD": 1000, "BTC": 1}}
+
+	report, err := reconcile.New(&mocks.AccountMock{}, reconcile.Config{}).
+		Reconcile(snapshot, opening.Add(2*time.Hour), quotes, nil, balances, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Breaks) != 1 || report.Breaks[0].Token != "USD" || report.Breaks[0].Difference != 3 {
+		t.Fatalf("breaks = %v, want a 3 USD break for the fee", report.Breaks)
+	}
+}
@@ -0,0 +1,70 @@
+package risk
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+// AuditEntry records one trading call seen by the Engine, whether allowed or rejected.
+type AuditEntry struct {
+	Time          time.Time         `json:"time"`
+	Action        string            `json:"action"`
+	ClientOrderId string            `json:"client_order_id,omitempty"`
+	FxQuoteId     string            `json:"fx_quote_id,omitempty"`
+	TokenPair     clients.TokenPair `json:"token_pair"`
+	Side          string            `json:"side"`
+	Quantity      clients.Quantity  `json:"quantity"`
+	Price         float64           `json:"price,omitempty"`
+	Notional      float64           `json:"notional,omitempty"`
+	Allowed       bool              `json:"allowed"`
+	Reason        Reason            `json:"reason,omitempty"`
+	Detail        string            `json:"detail,omitempty"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// AuditLog receives every AuditEntry. Implementations must be safe for concurrent use.
+type AuditLog interface {
+	Record(entry AuditEntry)
+}
+
+// MemoryAuditLog keeps entries in memory.
+type MemoryAuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (log *MemoryAuditLog) Record(entry AuditEntry) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.entries = append(log.entries, entry)
+}
+
+// Entries returns a copy of the recorded entries.
+func (log *MemoryAuditLog) Entries() []AuditEntry {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	entries := make([]AuditEntry, len(log.entries))
+	copy(entries, log.entries)
+	return entries
+}
+
+// JSONAuditLog writes one JSON object per line to an io.Writer, such as an append-only file.
+type JSONAuditLog struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewJSONAuditLog creates a JSONAuditLog writing to w.
+func NewJSONAuditLog(w io.Writer) *JSONAuditLog {
+	return &JSONAuditLog{encoder: json.NewEncoder(w)}
+}
+
+func (log *JSONAuditLog) Record(entry AuditEntry) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	_ = log.encoder.Encode(entry)
+}
@@ -0,0 +1,536 @@
+// Package risk wraps the FalconX trading calls with configurable pre-trade checks.
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+const defaultPlatform = "api"
+
+// Reason identifies which check rejected a call.
+type Reason string
+
+const (
+	ReasonPairNotAllowed   Reason = "pair_not_allowed"
+	ReasonOrderNotional    Reason = "order_notional"
+	ReasonDailyNotional    Reason = "daily_notional"
+	ReasonPosition         Reason = "position"
+	ReasonFatFinger        Reason = "fat_finger"
+	ReasonNoReferencePrice Reason = "no_reference_price"
+	ReasonGrossLimit       Reason = "gross_limit"
+	ReasonNetLimit         Reason = "net_limit"
+	ReasonCheckFailed      Reason = "check_failed"
+	ReasonKillSwitch       Reason = "kill_switch"
+)
+
+// Rejection is returned when a check refuses a call.
+type Rejection struct {
+	Reason Reason
+	Detail string
+}
+
+func (rejection *Rejection) Error() string {
+	return fmt.Sprintf("risk rejected (%s): %s", rejection.Reason, rejection.Detail)
+}
+
+// LocalRejection marks the call as refused before reaching FalconX, so the workflows
+// in clients do not treat it as a possible fill.
+func (rejection *Rejection) LocalRejection() {}
+
+var _ clients.LocalRejection = (*Rejection)(nil)
+
+// Limits configures the Engine. Zero values disable a check. Notional values are in
+// quote token, which is assumed to be USD when compared against the trade limits.
+type Limits struct {
+	// MaxOrderNotional caps a single quote or order.
+	MaxOrderNotional float64
+	// MaxDailyNotional caps the filled notional per UTC day.
+	MaxDailyNotional float64
+	// MaxPosition caps the absolute balance per token after a fill, using GetBalances.
+	MaxPosition map[string]float64
+	// MaxDeviationBps caps how far a quoted or limit price may sit from the last stream mid.
+	MaxDeviationBps float64
+	// AllowedPairs is the token pair whitelist. Empty allows every pair.
+	AllowedPairs []clients.TokenPair
+	// CheckTradeLimits enables the GetTradeLimits check; MinHeadroom is what must still
+	// be available under the gross and net limits after the trade.
+	CheckTradeLimits bool
+	MinHeadroom      float64
+	// Platform passed to GetTradeLimits. Defaults to api.
+	Platform string
+	// KillSwitch, when set, rejects every trading call while tripped, and is tripped by
+	// any rejection whose reason is listed in TripOn.
+	KillSwitch *clients.KillSwitch
+	TripOn     []Reason
+}
+
+// Engine is a clients.FalconX that runs pre-trade checks on GetQuote, ExecuteQuote and
+// PlaceOrder, and passes read-only calls straight through.
+type Engine struct {
+	clients.FalconX
+	limits Limits
+	audit  AuditLog
+
+	mu       sync.Mutex
+	day      time.Time
+	notional float64
+	// reserved is the notional of checked calls still in flight, held against
+	// MaxDailyNotional so concurrent calls cannot exceed it together.
+	reserved float64
+	mids     map[clients.TokenPair]float64
+	// quotes holds the quotes issued through GetQuote until they expire or their
+	// execution is settled.
+	quotes map[string]clients.QuoteResponse
+}
+
+var _ clients.FalconX = (*Engine)(nil)
+
+// NewEngine wraps client with limits. audit may be nil.
+func NewEngine(client clients.FalconX, limits Limits, audit AuditLog) *Engine {
+	if limits.Platform == "" {
+		limits.Platform = defaultPlatform
+	}
+	return &Engine{
+		FalconX: client,
+		limits:  limits,
+		audit:   audit,
+		mids:    make(map[clients.TokenPair]float64),
+		quotes:  make(map[string]clients.QuoteResponse),
+	}
+}
+
+// UpdateMid sets the reference mid for pair used by the fat-finger check.
+func (engine *Engine) UpdateMid(pair clients.TokenPair, mid float64) {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.mids[pair] = mid
+}
+
+// UpdateLadder takes the reference mid from a streamed ladder. It can be passed to
+// SocketClient.OnPriceLadder directly.
+func (engine *Engine) UpdateLadder(ladder clients.PriceLadder) {
+	if mid, ok := ladder.Mid(); ok {
+		engine.UpdateMid(ladder.TokenPair, mid)
+	}
+}
+
+// DailyNotional returns the notional filled through the engine today.
+func (engine *Engine) DailyNotional() float64 {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.rollDay()
+	return engine.notional
+}
+
+// GetQuote checks the pair and order size, requests the quote and checks its prices
+// against the stream mid.
+func (engine *Engine) GetQuote(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+	trade := trade{
+		action:        "get_quote",
+		pair:          quoteParams.TokenPair,
+		side:          quoteParams.Side,
+		quantity:      quoteParams.Quantity,
+		clientOrderId: quoteParams.ClientOrderId,
+	}
+	trade.price = engine.mid(trade.pair)
+
+	if err := engine.checkKillSwitch(trade); err != nil {
+		return engine.reject(trade, err)
+	}
+	if err := engine.checkPair(trade); err != nil {
+		return engine.reject(trade, err)
+	}
+	if err := engine.checkOrderNotional(trade); err != nil {
+		return engine.reject(trade, err)
+	}
+
+	quote, err := engine.FalconX.GetQuote(quoteParams)
+	trade.fxQuoteId = quote.FxQuoteId
+	if err != nil {
+		engine.record(trade, nil, err)
+		return quote, err
+	}
+
+	for _, price := range []float64{quote.BuyPrice.Or(0), quote.SellPrice.Or(0)} {
+		if price > 0 {
+			trade.price = price
+			if err := engine.checkDeviation(trade); err != nil {
+				engine.record(trade, err, nil)
+				return quote, err
+			}
+		}
+	}
+
+	engine.storeQuote(quote)
+	engine.record(trade, nil, nil)
+	return quote, nil
+}
+
+// ExecuteQuote runs every check against the quoted price of the executed side.
+func (engine *Engine) ExecuteQuote(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+	engine.mu.Lock()
+	quote, ok := engine.quotes[quoteParams.FxQuoteId]
+	engine.mu.Unlock()
+
+	if !ok {
+		status, err := engine.FalconX.GetQuoteStatus(quoteParams.FxQuoteId)
+		if err != nil {
+			unknown := trade{action: "execute_quote", fxQuoteId: quoteParams.FxQuoteId, side: quoteParams.Side}
+			return engine.reject(unknown, &Rejection{Reason: ReasonCheckFailed, Detail: fmt.Sprintf("GetQuoteStatus: %v", err)})
+		}
+		quote = status
+	}
+
+	trade := trade{
+		action:        "execute_quote",
+		pair:          quote.TokenPair,
+		side:          quoteParams.Side,
+		quantity:      quote.Quantity,
+		price:         executedPrice(quoteParams.Side, quote.BuyPrice, quote.SellPrice),
+		fxQuoteId:     quoteParams.FxQuoteId,
+		clientOrderId: quote.ClientOrderId,
+	}
+
+	reserved, err := engine.checkTrade(trade)
+	if err != nil {
+		return engine.reject(trade, err)
+	}
+
+	response, err := engine.FalconX.ExecuteQuote(quoteParams)
+	engine.record(trade, nil, err)
+	filled := 0.0
+	if err == nil && response.IsFilled {
+		filled = trade.notional()
+	}
+	// An answer from FalconX settles the quote; only an ambiguous failure may be retried.
+	var apiErr clients.Error
+	if err == nil || errors.As(err, &apiErr) && apiErr.Code < 500 {
+		engine.mu.Lock()
+		delete(engine.quotes, quoteParams.FxQuoteId)
+		engine.mu.Unlock()
+	}
+	engine.settle(reserved, filled)
+	return response, err
+}
+
+// storeQuote keeps quote for ExecuteQuote until it expires, and drops the quotes that
+// already have.
+func (engine *Engine) storeQuote(quote clients.QuoteResponse) {
+	now := time.Now()
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+
+	for id, stored := range engine.quotes {
+		if !now.Before(stored.ExpiryTime) {
+			delete(engine.quotes, id)
+		}
+	}
+	if now.Before(quote.ExpiryTime) {
+		engine.quotes[quote.FxQuoteId] = quote
+	}
+}
+
+// PlaceOrder runs every check, pricing market orders at the stream mid and limit orders
+// at their limit price.
+func (engine *Engine) PlaceOrder(orderParams clients.OrderRequest) (clients.OrderResponse, error) {
+	trade := trade{
+		action:        "place_order",
+		pair:          orderParams.TokenPair,
+		side:          orderParams.Side,
+		quantity:      orderParams.Quantity,
+		price:         engine.mid(orderParams.TokenPair),
+		clientOrderId: orderParams.ClientOrderId,
+	}
+	if orderParams.OrderType == "limit" && orderParams.LimitPrice > 0 {
+		trade.price = orderParams.LimitPrice
+	}
+
+	reserved, err := engine.checkTrade(trade)
+	if err != nil {
+		response, rejection := engine.reject(trade, err)
+		return clients.OrderResponse{Status: response.Status, Error: response.Error}, rejection
+	}
+
+	response, err := engine.FalconX.PlaceOrder(orderParams)
+	trade.fxQuoteId = response.FxQuoteId
+	engine.record(trade, nil, err)
+	filled := 0.0
+	if err == nil && response.IsFilled {
+		fill := trade
+		if price := executedPrice(orderParams.Side, response.BuyPrice, response.SellPrice); price > 0 {
+			fill.price = price
+		}
+		filled = fill.notional()
+	}
+	engine.settle(reserved, filled)
+	return response, err
+}
+
+type trade struct {
+	action        string
+	pair          clients.TokenPair
+	side          string
+	quantity      clients.Quantity
+	price         float64
+	fxQuoteId     string
+	clientOrderId string
+}
+
+// notional is the trade's size in quote token, or zero when it cannot be priced.
+func (t trade) notional() float64 {
+	if t.quantity.Token == t.pair.QuoteToken {
+		return t.quantity.Value
+	}
+	return t.quantity.Value * t.price
+}
+
+// baseQuantity is the trade's size in base token, or zero when it cannot be priced.
+func (t trade) baseQuantity() float64 {
+	if t.quantity.Token == t.pair.QuoteToken {
+		if t.price <= 0 {
+			return 0
+		}
+		return t.quantity.Value / t.price
+	}
+	return t.quantity.Value
+}
+
+// checkTrade runs every check and, once all others passed, reserves the trade's
+// notional against MaxDailyNotional. The reservation must be released with settle.
+func (engine *Engine) checkTrade(t trade) (float64, error) {
+	checks := []func(trade) error{
+		engine.checkKillSwitch,
+		engine.checkPair,
+		engine.checkOrderNotional,
+		engine.checkDeviation,
+		engine.checkPosition,
+		engine.checkTradeLimits,
+	}
+	for _, check := range checks {
+		if err := check(t); err != nil {
+			return 0, err
+		}
+	}
+	return engine.reserveDailyNotional(t)
+}
+
+func (engine *Engine) checkKillSwitch(t trade) error {
+	if err := engine.limits.KillSwitch.Check(); err != nil {
+		return &Rejection{Reason: ReasonKillSwitch, Detail: err.Error()}
+	}
+	return nil
+}
+
+func (engine *Engine) checkPair(t trade) error {
+	if len(engine.limits.AllowedPairs) == 0 {
+		return nil
+	}
+	for _, pair := range engine.limits.AllowedPairs {
+		if pair == t.pair {
+			return nil
+		}
+	}
+	return &Rejection{Reason: ReasonPairNotAllowed, Detail: fmt.Sprintf("%s/%s not whitelisted", t.pair.BaseToken, t.pair.QuoteToken)}
+}
+
+func (engine *Engine) checkOrderNotional(t trade) error {
+	if engine.limits.MaxOrderNotional <= 0 {
+		return nil
+	}
+	notional := t.notional()
+	if notional <= 0 {
+		return &Rejection{Reason: ReasonNoReferencePrice, Detail: "cannot price order to check notional"}
+	}
+	if notional > engine.limits.MaxOrderNotional {
+		return &Rejection{Reason: ReasonOrderNotional, Detail: fmt.Sprintf("notional %.2f exceeds %.2f", notional, engine.limits.MaxOrderNotional)}
+	}
+	return nil
+}
+
+// reserveDailyNotional checks the trade against MaxDailyNotional, counting calls in
+// flight, and reserves its notional. It returns the amount reserved.
+func (engine *Engine) reserveDailyNotional(t trade) (float64, error) {
+	if engine.limits.MaxDailyNotional <= 0 {
+		return 0, nil
+	}
+	notional := t.notional()
+	if notional <= 0 {
+		return 0, &Rejection{Reason: ReasonNoReferencePrice, Detail: "cannot price order to check daily notional"}
+	}
+
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.rollDay()
+	if total := engine.notional + engine.reserved + notional; total > engine.limits.MaxDailyNotional {
+		return 0, &Rejection{Reason: ReasonDailyNotional, Detail: fmt.Sprintf("daily notional %.2f would exceed %.2f", total, engine.limits.MaxDailyNotional)}
+	}
+	engine.reserved += notional
+	return notional, nil
+}
+
+func (engine *Engine) checkDeviation(t trade) error {
+	if engine.limits.MaxDeviationBps <= 0 {
+		return nil
+	}
+	mid := engine.mid(t.pair)
+	if mid <= 0 {
+		return &Rejection{Reason: ReasonNoReferencePrice, Detail: fmt.Sprintf("no stream mid for %s/%s", t.pair.BaseToken, t.pair.QuoteToken)}
+	}
+	if deviation := math.Abs(t.price-mid) / mid * 10000; deviation > engine.limits.MaxDeviationBps {
+		return &Rejection{Reason: ReasonFatFinger, Detail: fmt.Sprintf("price %v is %.1f bps from mid %v, max %.1f", t.price, deviation, mid, engine.limits.MaxDeviationBps)}
+	}
+	return nil
+}
+
+func (engine *Engine) checkPosition(t trade) error {
+	if len(engine.limits.MaxPosition) == 0 {
+		return nil
+	}
+	baseLimit, checkBase := engine.limits.MaxPosition[t.pair.BaseToken]
+	quoteLimit, checkQuote := engine.limits.MaxPosition[t.pair.QuoteToken]
+	if !checkBase && !checkQuote {
+		return nil
+	}
+	if checkBase && t.baseQuantity() <= 0 || checkQuote && t.notional() <= 0 {
+		return &Rejection{Reason: ReasonNoReferencePrice, Detail: "cannot price order to check positions"}
+	}
+
+	balances, err := engine.FalconX.GetBalances()
+	if err != nil {
+		return &Rejection{Reason: ReasonCheckFailed, Detail: fmt.Sprintf("GetBalances: %v", err)}
+	}
+	positions := make(map[string]float64)
+	for _, balance := range balances {
+		positions[balance.Token] += balance.Balance
+	}
+
+	baseDelta, quoteDelta := t.baseQuantity(), -t.notional()
+	if t.side == "sell" {
+		baseDelta, quoteDelta = -baseDelta, -quoteDelta
+	}
+	if checkBase {
+		if projected := positions[t.pair.BaseToken] + baseDelta; math.Abs(projected) > baseLimit {
+			return &Rejection{Reason: ReasonPosition, Detail: fmt.Sprintf("%s position %v would exceed %v", t.pair.BaseToken, projected, baseLimit)}
+		}
+	}
+	if checkQuote {
+		if projected := positions[t.pair.QuoteToken] + quoteDelta; math.Abs(projected) > quoteLimit {
+			return &Rejection{Reason: ReasonPosition, Detail: fmt.Sprintf("%s position %v would exceed %v", t.pair.QuoteToken, projected, quoteLimit)}
+		}
+	}
+	return nil
+}
+
+func (engine *Engine) checkTradeLimits(t trade) error {
+	if !engine.limits.CheckTradeLimits {
+		return nil
+	}
+	notional := t.notional()
+	if notional <= 0 {
+		return &Rejection{Reason: ReasonNoReferencePrice, Detail: "cannot price order to check trade limits"}
+	}
+	limits, err := engine.FalconX.GetTradeLimits(engine.limits.Platform)
+	if err != nil {
+		return &Rejection{Reason: ReasonCheckFailed, Detail: fmt.Sprintf("GetTradeLimits: %v", err)}
+	}
+
+	if left := limits.GrossLimits.Available - notional; left < engine.limits.MinHeadroom {
+		return &Rejection{Reason: ReasonGrossLimit, Detail: fmt.Sprintf("%.2f left under gross limit after trade, need %.2f", left, engine.limits.MinHeadroom)}
+	}
+	if left := limits.NetLimits.Available - notional; left < engine.limits.MinHeadroom {
+		return &Rejection{Reason: ReasonNetLimit, Detail: fmt.Sprintf("%.2f left under net limit after trade, need %.2f", left, engine.limits.MinHeadroom)}
+	}
+	return nil
+}
+
+func (engine *Engine) mid(pair clients.TokenPair) float64 {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	return engine.mids[pair]
+}
+
+// settle releases a reservation made by checkTrade and books the filled notional,
+// zero when the call did not fill.
+func (engine *Engine) settle(reserved, filled float64) {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.reserved -= reserved
+	engine.rollDay()
+	engine.notional += filled
+}
+
+// rollDay resets the daily notional at UTC midnight. Callers must hold engine.mu.
+func (engine *Engine) rollDay() {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if !today.Equal(engine.day) {
+		engine.day = today
+		engine.notional = 0
+	}
+}
+
+func (engine *Engine) reject(t trade, err error) (clients.QuoteResponse, error) {
+	engine.record(t, err, nil)
+	reason := ""
+	if rejection, ok := err.(*Rejection); ok {
+		reason = string(rejection.Reason)
+	}
+	return clients.QuoteResponse{
+		Status: "failure",
+		Error:  clients.FalconXError{Code: reason, Reason: err.Error()},
+	}, err
+}
+
+func (engine *Engine) record(t trade, rejection error, callErr error) {
+	if r, ok := rejection.(*Rejection); ok {
+		engine.tripOn(r)
+	}
+	if engine.audit == nil {
+		return
+	}
+	entry := AuditEntry{
+		Time:          time.Now().UTC(),
+		Action:        t.action,
+		ClientOrderId: t.clientOrderId,
+		FxQuoteId:     t.fxQuoteId,
+		TokenPair:     t.pair,
+		Side:          t.side,
+		Quantity:      t.quantity,
+		Price:         t.price,
+		Notional:      t.notional(),
+		Allowed:       rejection == nil,
+	}
+	if r, ok := rejection.(*Rejection); ok {
+		entry.Reason = r.Reason
+		entry.Detail = r.Detail
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	}
+	engine.audit.Record(entry)
+}
+
+// tripOn trips the kill switch when rejection is a configured breach.
+func (engine *Engine) tripOn(rejection *Rejection) {
+	if engine.limits.KillSwitch == nil || rejection.Reason == ReasonKillSwitch {
+		return
+	}
+	for _, reason := range engine.limits.TripOn {
+		if reason == rejection.Reason {
+			_ = engine.limits.KillSwitch.Trip(fmt.Sprintf("risk breach: %v", rejection))
+			return
+		}
+	}
+}
+
+func executedPrice(side string, buyPrice, sellPrice clients.NullFloat64) float64 {
+	if side == "sell" {
+		return sellPrice.Or(0)
+	}
+	return buyPrice.Or(0)
+}
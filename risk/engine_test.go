@@ -0,0 +1,314 @@
+package risk_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/clients/mocks"
+	"github.com/falconxio/falconx-go/risk"
+)
+
+var btcUSD = clients.TokenPair{BaseToken: "BTC", QuoteToken: "USD"}
+
+// limitOrder is a limit order for quantity BTC at 100 USD.
+func limitOrder(quantity float64) clients.OrderRequest {
+	return clients.OrderRequest{
+		TokenPair:  btcUSD,
+		Quantity:   clients.Quantity{Token: "BTC", Value: quantity},
+		Side:       "buy",
+		OrderType:  "limit",
+		LimitPrice: 100,
+	}
+}
+
+func filledOrder(orderParams clients.OrderRequest) (clients.OrderResponse, error) {
+	return clients.OrderResponse{
+		IsFilled:     true,
+		BuyPrice:     clients.NewNullFloat64(orderParams.LimitPrice),
+		SideExecuted: clients.NewNullSide(orderParams.Side),
+	}, nil
+}
+
+// reason returns the Reason of a *risk.Rejection, or "" for any other error.
+func reason(err error) risk.Reason {
+	var rejection *risk.Rejection
+	if errors.As(err, &rejection) {
+		return rejection.Reason
+	}
+	return ""
+}
+
+func TestDailyNotionalReservedWhileInFlight(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	client := &mocks.FalconXMock{
+		PlaceOrderFunc: func(orderParams clients.OrderRequest) (clients.OrderResponse, error) {
+			entered <- struct{}{}
+			<-release
+			return filledOrder(orderParams)
+		},
+	}
+	engine := risk.NewEngine(client, risk.Limits{MaxDailyNotional: 150}, nil)
+
+	first := make(chan error)
+	go func() {
+		_, err := engine.PlaceOrder(limitOrder(1))
+		first <- err
+	}()
+	<-entered
+
+	// The first order has not filled yet, but its 100 is reserved.
+	if _, err := engine.PlaceOrder(limitOrder(1)); reason(err) != risk.ReasonDailyNotional {
+		t.Fatalf("concurrent order err = %v, want %s", err, risk.ReasonDailyNotional)
+	}
+	close(release)
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+	if got := engine.DailyNotional(); got != 100 {
+		t.Errorf("DailyNotional() = %v, want 100", got)
+	}
+}
+
+func TestDailyNotionalReleasedOnFailure(t *testing.T) {
+	fail := true
+	client := &mocks.FalconXMock{
+		PlaceOrderFunc: func(orderParams clients.OrderRequest) (clients.OrderResponse, error) {
+			if fail {
+				return clients.OrderResponse{}, clients.Error{Code: 400, Reason: "rejected"}
+			}
+			return filledOrder(orderParams)
+		},
+	}
+	engine := risk.NewEngine(client, risk.Limits{MaxDailyNotional: 150}, nil)
+
+	if _, err := engine.PlaceOrder(limitOrder(1)); err == nil {
+		t.Fatal("failing order succeeded")
+	}
+	fail = false
+	if _, err := engine.PlaceOrder(limitOrder(1)); err != nil {
+		t.Fatalf("order after a failed one: %v", err)
+	}
+	if got := engine.DailyNotional(); got != 100 {
+		t.Errorf("DailyNotional() = %v, want 100", got)
+	}
+}
+
+func TestUnpricedOrderRejected(t *testing.T) {
+	// A market order without a stream mid cannot be priced.
+	market := clients.OrderRequest{
+		TokenPair: btcUSD,
+		Quantity:  clients.Quantity{Token: "BTC", Value: 1},
+		Side:      "buy",
+		OrderType: "market",
+	}
+	tests := []struct {
+		name   string
+		limits risk.Limits
+	}{
+		{"position in quote token", risk.Limits{MaxPosition: map[string]float64{"USD": 1000}}},
+		{"trade limits", risk.Limits{CheckTradeLimits: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &mocks.FalconXMock{
+				GetBalancesFunc: func() ([]clients.Balance, error) { return nil, nil },
+				GetTradeLimitsFunc: func(platform string) (clients.TradeLimits, error) {
+					return clients.TradeLimits{}, nil
+				},
+			}
+			engine := risk.NewEngine(client, test.limits, nil)
+			if _, err := engine.PlaceOrder(market); reason(err) != risk.ReasonNoReferencePrice {
+				t.Errorf("err = %v, want %s", err, risk.ReasonNoReferencePrice)
+			}
+			if len(client.PlaceOrderCalls()) != 0 {
+				t.Error("unpriced order was sent")
+			}
+		})
+	}
+}
+
+func TestRejectionReasons(t *testing.T) {
+	tripped, err := clients.NewKillSwitch("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tripped.Trip("test")
+
+	balances := func() ([]clients.Balance, error) {
+		return []clients.Balance{{Token: "BTC", Balance: 4}, {Token: "USD", Balance: 1000}}, nil
+	}
+	tradeLimits := func(gross, net float64) func(string) (clients.TradeLimits, error) {
+		return func(string) (clients.TradeLimits, error) {
+			return clients.TradeLimits{
+				GrossLimits: clients.TradeLimit{Available: gross},
+				NetLimits:   clients.TradeLimit{Available: net},
+			}, nil
+		}
+	}
+
+	tests := []struct {
+		reason risk.Reason
+		limits risk.Limits
+		// balances and tradeLimits back the client's GetBalances and GetTradeLimits.
+		balances    func() ([]clients.Balance, error)
+		tradeLimits func(string) (clients.TradeLimits, error)
+		// mid is the stream mid; the order is limited at 100.
+		mid float64
+	}{
+		{reason: risk.ReasonKillSwitch, limits: risk.Limits{KillSwitch: tripped}},
+		{reason: risk.ReasonPairNotAllowed, limits: risk.Limits{
+			AllowedPairs: []clients.TokenPair{{BaseToken: "ETH", QuoteToken: "USD"}}}},
+		{reason: risk.ReasonOrderNotional, limits: risk.Limits{MaxOrderNotional: 99}},
+		{reason: risk.ReasonDailyNotional, limits: risk.Limits{MaxDailyNotional: 99}},
+		{reason: risk.ReasonNoReferencePrice, limits: risk.Limits{MaxDeviationBps: 50}},
+		{reason: risk.ReasonFatFinger, limits: risk.Limits{MaxDeviationBps: 50}, mid: 99},
+		{reason: risk.ReasonPosition, limits: risk.Limits{MaxPosition: map[string]float64{"BTC": 4.5}},
+			balances: balances},
+		{reason: risk.ReasonPosition, limits: risk.Limits{MaxPosition: map[string]float64{"USD": 850}},
+			balances: balances},
+		{reason: risk.ReasonCheckFailed, limits: risk.Limits{MaxPosition: map[string]float64{"BTC": 10}},
+			balances: func() ([]clients.Balance, error) { return nil, errors.New("unavailable") }},
+		{reason: risk.ReasonGrossLimit, limits: risk.Limits{CheckTradeLimits: true, MinHeadroom: 10},
+			tradeLimits: tradeLimits(109, 1000)},
+		{reason: risk.ReasonNetLimit, limits: risk.Limits{CheckTradeLimits: true, MinHeadroom: 10},
+			tradeLimits: tradeLimits(1000, 109)},
+	}
+	for _, test := range tests {
+		t.Run(string(test.reason), func(t *testing.T) {
+			client := &mocks.FalconXMock{
+				GetBalancesFunc:    test.balances,
+				GetTradeLimitsFunc: test.tradeLimits,
+				PlaceOrderFunc:     filledOrder,
+			}
+			audit := &risk.MemoryAuditLog{}
+			engine := risk.NewEngine(client, test.limits, audit)
+			if test.mid > 0 {
+				engine.UpdateMid(btcUSD, test.mid)
+			}
+
+			response, err := engine.PlaceOrder(limitOrder(1))
+			if reason(err) != test.reason {
+				t.Fatalf("err = %v, want %s", err, test.reason)
+			}
+			var local clients.LocalRejection
+			if !errors.As(err, &local) {
+				t.Error("rejection is not a clients.LocalRejection")
+			}
+			if response.Status != "failure" || response.Error.Code != string(test.reason) {
+				t.Errorf("response status %q code %q, want failure with %s", response.Status, response.Error.Code, test.reason)
+			}
+			if len(client.PlaceOrderCalls()) != 0 {
+				t.Error("rejected order was sent")
+			}
+			entries := audit.Entries()
+			if len(entries) != 1 || entries[0].Allowed || entries[0].Reason != test.reason {
+				t.Errorf("audit entries = %+v, want one %s rejection", entries, test.reason)
+			}
+		})
+	}
+}
+
+func TestAllowedOrderIsAudited(t *testing.T) {
+	client := &mocks.FalconXMock{PlaceOrderFunc: filledOrder}
+	audit := &risk.MemoryAuditLog{}
+	engine := risk.NewEngine(client, risk.Limits{MaxOrderNotional: 100, MaxDailyNotional: 1000}, audit)
+
+	if _, err := engine.PlaceOrder(limitOrder(1)); err != nil {
+		t.Fatal(err)
+	}
+	if notional := engine.DailyNotional(); notional != 100 {
+		t.Errorf("daily notional = %v, want 100", notional)
+	}
+	entries := audit.Entries()
+	if len(entries) != 1 || !entries[0].Allowed || entries[0].Notional != 100 {
+		t.Errorf("audit entries = %+v, want one allowed entry for 100", entries)
+	}
+}
+
+func TestBreachTripsKillSwitch(t *testing.T) {
+	killSwitch, err := clients.NewKillSwitch("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &mocks.FalconXMock{PlaceOrderFunc: filledOrder}
+	engine := risk.NewEngine(client, risk.Limits{
+		MaxOrderNotional: 150,
+		KillSwitch:       killSwitch,
+		TripOn:           []risk.Reason{risk.ReasonOrderNotional},
+	}, nil)
+
+	if _, err := engine.PlaceOrder(limitOrder(2)); reason(err) != risk.ReasonOrderNotional {
+		t.Fatalf("err = %v, want %s", err, risk.ReasonOrderNotional)
+	}
+	if !killSwitch.Tripped() {
+		t.Fatal("order notional breach did not trip the kill switch")
+	}
+	if _, err := engine.PlaceOrder(limitOrder(1)); reason(err) != risk.ReasonKillSwitch {
+		t.Errorf("err = %v, want %s", err, risk.ReasonKillSwitch)
+	}
+}
+
+func TestStoredQuotesAreBounded(t *testing.T) {
+	var issued int
+	ttl := time.Hour
+	client := &mocks.FalconXMock{
+		GetQuoteFunc: func(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+			issued++
+			return clients.QuoteResponse{
+				FxQuoteId:  fmt.Sprintf("q%d", issued),
+				TokenPair:  btcUSD,
+				Quantity:   quoteParams.Quantity,
+				BuyPrice:   clients.NewNullFloat64(100),
+				ExpiryTime: time.Now().Add(ttl),
+			}, nil
+		},
+		ExecuteQuoteFunc: func(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+			if quoteParams.FxQuoteId == "q2" {
+				return clients.QuoteResponse{}, clients.Error{Code: 400, Reason: "Quote expired"}
+			}
+			if quoteParams.FxQuoteId == "q3" {
+				return clients.QuoteResponse{}, clients.Error{Code: 502, Reason: "bad gateway"}
+			}
+			return clients.QuoteResponse{FxQuoteId: quoteParams.FxQuoteId, IsFilled: false}, nil
+		},
+	}
+	engine := risk.NewEngine(client, risk.Limits{}, nil)
+	request := clients.QuoteRequest{TokenPair: btcUSD, Quantity: clients.Quantity{Token: "BTC", Value: 1}, Side: "buy"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := engine.GetQuote(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := engine.StoredQuotes(); n != 3 {
+		t.Fatalf("%d quotes stored, want 3", n)
+	}
+
+	// An unfilled answer and a rejection settle their quotes; an ambiguous failure does not.
+	for _, id := range []string{"q1", "q2", "q3"} {
+		engine.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: id, Side: "buy"})
+	}
+	if n := engine.StoredQuotes(); n != 1 {
+		t.Errorf("%d quotes stored after executions, want only the ambiguous one", n)
+	}
+
+	// Quotes are dropped once expired, so never executing them does not grow the map.
+	ttl = 10 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		if _, err := engine.GetQuote(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(2 * ttl)
+	ttl = -time.Second
+	if _, err := engine.GetQuote(request); err != nil {
+		t.Fatal(err)
+	}
+	if n := engine.StoredQuotes(); n != 1 {
+		t.Errorf("%d quotes stored after they expired, want 1", n)
+	}
+}
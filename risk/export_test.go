@@ -0,0 +1,8 @@
+package risk
+
+// StoredQuotes returns the number of quotes the engine keeps for ExecuteQuote.
+func (engine *Engine) StoredQuotes() int {
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	return len(engine.quotes)
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"github.com/falconxio/falconx-go/client_examples"
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/config"
 	"log"
 	"strings"
 )
@@ -19,10 +21,11 @@ func isFlagPassed(name string) bool {
 
 func main() {
 
-	// Mandatory Arguments
-	var APIKey *string = flag.String("api_key", "XXX", "API Key provided by FalxonX")
-	var Secret *string = flag.String("secret", "XXX", "Secret provided by FalxonX")
-	var Passphrase *string = flag.String("passphrase", "XXX", "Pass Phrase provided by FalxonX")
+	// Credentials are taken from these flags when given, otherwise from a config profile.
+	var APIKey *string = flag.String("api_key", "", "API Key provided by FalxonX")
+	var Secret *string = flag.String("secret", "", "Secret provided by FalxonX")
+	var Passphrase *string = flag.String("passphrase", "", "Pass Phrase provided by FalxonX")
+	var Profile *string = flag.String("profile", "", "Config profile to read credentials and hosts from")
 
 	// Optional Arguments
 	var example_set *string = flag.String("example_set", "rest", "Example Set to run")
@@ -33,20 +36,48 @@ func main() {
 	isSecretPassed := isFlagPassed("secret")
 	isPassphrasePassed := isFlagPassed("passphrase")
 
-	if !isAPIKeyPassed {
-		panic("api_key argument missing! ( eg. go run run_examples.go -api_key=<api_key>")
-	} else if !isSecretPassed {
-		panic("secret argument missing! ( eg. go run run_examples.go -secret=<secret>")
-	} else if !isPassphrasePassed {
-		panic("passphrase argument missing! ( eg. go run run_examples.go -passphrase=<passphrase>")
+	restConfig := clients.RestClientConfig{BaseURL: *Host, APIKey: *APIKey, Secret: *Secret, Passphrase: *Passphrase}
+	socketConfig := clients.SocketClientConfig{Host: *Host, APIKey: *APIKey, Secret: *Secret, Passphrase: *Passphrase}
+	if !isAPIKeyPassed || !isSecretPassed || !isPassphrasePassed {
+		profile, err := config.Load(config.Options{Profile: *Profile})
+		if err != nil {
+			log.Fatalf("api_key, secret and passphrase flags missing and no usable profile: %v", err)
+		}
+		// The profile builds the client configs, so secret_file and signer_socket
+		// profiles sign through their Signer. Flags then replace what they set.
+		if *example_set == "websocket" {
+			if len(*Host) > 0 {
+				profile.WSHost = *Host
+			}
+			if socketConfig, err = profile.SocketClientConfig(); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			if len(*Host) > 0 {
+				profile.BaseURL = *Host
+			}
+			if restConfig, err = profile.RestClientConfig(); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if isAPIKeyPassed {
+			restConfig.APIKey, socketConfig.APIKey = *APIKey, *APIKey
+		}
+		if isSecretPassed {
+			restConfig.Secret, restConfig.Signer = *Secret, nil
+			socketConfig.Secret, socketConfig.Signer = *Secret, nil
+		}
+		if isPassphrasePassed {
+			restConfig.Passphrase, socketConfig.Passphrase = *Passphrase, *Passphrase
+		}
 	}
 	possibleExampleSets := []string{"websocket", "rest"}
 
 	log.Printf("example_set: %s", *example_set)
 	if *example_set == "websocket" {
-		client_examples.RunWebSocketExamples(*APIKey, *Secret, *Passphrase, *Host)
+		client_examples.RunWebSocketExamplesWithConfig(socketConfig)
 	} else if *example_set == "rest" {
-		client_examples.RunRestExamples(*APIKey, *Secret, *Passphrase, *Host)
+		client_examples.RunRestExamplesWithConfig(restConfig)
 	} else {
 		log.Fatalf("Example Set Not Found! Example sets available: %s", strings.Join(possibleExampleSets[:], ","))
 	}
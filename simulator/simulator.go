@@ -0,0 +1,623 @@
+package simulator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+)
+
+const (
+	defaultQuoteTTL = 10 * time.Second
+	defaultPlatform = "api"
+
+	statusSuccess = "success"
+	statusFailure = "failure"
+)
+
+// PriceSource supplies the reference mid price the simulator quotes around.
+type PriceSource interface {
+	Price(pair clients.TokenPair) (float64, error)
+}
+
+// PriceSourceFunc adapts a plain function to a PriceSource.
+type PriceSourceFunc func(pair clients.TokenPair) (float64, error)
+
+func (f PriceSourceFunc) Price(pair clients.TokenPair) (float64, error) {
+	return f(pair)
+}
+
+// PairLister is implemented by price sources that know which pairs they price.
+// GetTradingPairs lists them when Config.Pairs is empty.
+type PairLister interface {
+	Pairs() []clients.TokenPair
+}
+
+// StaticPrices is a fixed mid price per token pair.
+type StaticPrices map[clients.TokenPair]float64
+
+// Pairs returns the priced pairs, sorted by base and then quote token.
+func (p StaticPrices) Pairs() []clients.TokenPair {
+	pairs := make([]clients.TokenPair, 0, len(p))
+	for pair := range p {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].BaseToken != pairs[j].BaseToken {
+			return pairs[i].BaseToken < pairs[j].BaseToken
+		}
+		return pairs[i].QuoteToken < pairs[j].QuoteToken
+	})
+	return pairs
+}
+
+func (p StaticPrices) Price(pair clients.TokenPair) (float64, error) {
+	price, ok := p[pair]
+	if !ok {
+		return 0, fmt.Errorf("simulator: no price for %s/%s", pair.BaseToken, pair.QuoteToken)
+	}
+	return price, nil
+}
+
+// Config describes the simulated venue.
+type Config struct {
+	// Prices provides the mid price for each quote.
+	Prices PriceSource
+	// Pairs restricts tradable pairs. When empty every pair the price source knows is allowed.
+	Pairs []clients.TokenPair
+	// SpreadBps is the full bid/ask spread; half of it is applied on each side of the mid.
+	SpreadBps float64
+	// QuoteTTL is the time between t_quote and t_expiry. Defaults to 10s.
+	QuoteTTL time.Duration
+	// GrossFeeBps and RebateBps are charged on every fill; the net fee is their difference.
+	GrossFeeBps float64
+	RebateBps   float64
+	// Balances seeds the account, keyed by token.
+	Balances map[string]float64
+	// AllowNegativeBalances lets fills draw the account below zero, like a credit line.
+	AllowNegativeBalances bool
+	// TradeSizes reports the per-pair limits in quote token; fills outside them are rejected.
+	TradeSizes map[clients.TokenPair]clients.TradeSizeLimit
+	// GrossLimit and NetLimit are the USD trade limits reported by GetTradeLimits.
+	GrossLimit float64
+	NetLimit   float64
+	// TraderEmail is echoed on every response.
+	TraderEmail string
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Simulator is an in-memory FalconX venue implementing clients.FalconX.
+// It never touches the network, so strategies can be exercised without moving money.
+type Simulator struct {
+	config Config
+
+	mu        sync.Mutex
+	balances  map[string]float64
+	quotes    map[string]*clients.QuoteResponse
+	executed  []clients.QuoteResponse
+	transfers []clients.Transfer
+	volumes   []fill
+}
+
+var _ clients.FalconX = (*Simulator)(nil)
+
+type fill struct {
+	time     time.Time
+	usdValue float64
+}
+
+// New creates a Simulator from config.
+func New(config Config) *Simulator {
+	if config.QuoteTTL <= 0 {
+		config.QuoteTTL = defaultQuoteTTL
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	balances := make(map[string]float64, len(config.Balances))
+	for token, value := range config.Balances {
+		balances[token] = value
+	}
+
+	return &Simulator{
+		config:   config,
+		balances: balances,
+		quotes:   make(map[string]*clients.QuoteResponse),
+	}
+}
+
+// Deposit credits the account and records a deposit transfer.
+func (sim *Simulator) Deposit(token string, quantity float64) {
+	sim.transfer("deposit", token, quantity)
+}
+
+// Withdraw debits the account and records a withdrawal transfer.
+func (sim *Simulator) Withdraw(token string, quantity float64) {
+	sim.transfer("withdrawal", token, -quantity)
+}
+
+func (sim *Simulator) transfer(kind, token string, delta float64) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	sim.balances[token] += delta
+	sim.transfers = append(sim.transfers, clients.Transfer{
+		Type:       kind,
+		Platform:   defaultPlatform,
+		Token:      token,
+		Quantity:   math.Abs(delta),
+		CreateTime: sim.config.Now().UTC(),
+		Status:     "completed",
+	})
+}
+
+// GetTradingPairs returns the configured pairs or, when none are configured, the pairs
+// of a price source that is a PairLister.
+func (sim *Simulator) GetTradingPairs() ([]clients.TokenPair, error) {
+	if lister, ok := sim.config.Prices.(PairLister); ok && len(sim.config.Pairs) == 0 {
+		return lister.Pairs(), nil
+	}
+	pairs := make([]clients.TokenPair, len(sim.config.Pairs))
+	copy(pairs, sim.config.Pairs)
+	return pairs, nil
+}
+
+// GetQuote prices a buy, sell or two_way quote around the source mid.
+func (sim *Simulator) GetQuote(quoteParams clients.QuoteRequest) (clients.QuoteResponse, error) {
+	if err := validateSide(quoteParams.Side, true); err != nil {
+		return failedQuote(err), err
+	}
+
+	buyPrice, sellPrice, err := sim.prices(quoteParams.TokenPair)
+	if err != nil {
+		return failedQuote(err), err
+	}
+
+	now := sim.config.Now().UTC()
+	quote := clients.QuoteResponse{
+		Status:        statusSuccess,
+		FxQuoteId:     newQuoteID(),
+		Platform:      defaultPlatform,
+		TokenPair:     quoteParams.TokenPair,
+		Quantity:      quoteParams.Quantity,
+		SideRequested: quoteParams.Side,
+		QuoteTime:     now,
+		ExpiryTime:    now.Add(sim.config.QuoteTTL),
+		TraderEmail:   sim.config.TraderEmail,
+		ClientOrderId: quoteParams.ClientOrderId,
+	}
+	if quoteParams.Side != "sell" {
+		quote.BuyPrice = clients.NewNullFloat64(buyPrice)
+	}
+	if quoteParams.Side != "buy" {
+		quote.SellPrice = clients.NewNullFloat64(sellPrice)
+	}
+
+	sim.mu.Lock()
+	stored := quote
+	sim.quotes[quote.FxQuoteId] = &stored
+	sim.mu.Unlock()
+
+	return quote, nil
+}
+
+// ExecuteQuote fills a previously issued quote, provided it is executed strictly before t_expiry.
+func (sim *Simulator) ExecuteQuote(quoteParams clients.QuoteExecutionRequest) (clients.QuoteResponse, error) {
+	if err := validateSide(quoteParams.Side, false); err != nil {
+		return failedQuote(err), err
+	}
+
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	quote, ok := sim.quotes[quoteParams.FxQuoteId]
+	if !ok {
+		err := badRequest("Quote not found")
+		return failedQuote(err), err
+	}
+	if quote.IsFilled {
+		err := badRequest("Quote already executed")
+		return failedQuote(err), err
+	}
+	if quote.SideRequested != "two_way" && quote.SideRequested != quoteParams.Side {
+		err := badRequest(fmt.Sprintf("Quote was requested for side %s", quote.SideRequested))
+		return failedQuote(err), err
+	}
+
+	now := sim.config.Now().UTC()
+	if !now.Before(quote.ExpiryTime) {
+		err := badRequest("Quote expired")
+		return failedQuote(err), err
+	}
+
+	price := quote.BuyPrice.Float64
+	if quoteParams.Side == "sell" {
+		price = quote.SellPrice.Float64
+	}
+
+	fees, err := sim.settle(quote.TokenPair, quote.Quantity, quoteParams.Side, price, now)
+	if err != nil {
+		return failedQuote(err), err
+	}
+
+	quote.IsFilled = true
+	quote.ExecutionTime = clients.NewNullTime(now)
+	quote.SideExecuted = clients.NewNullSide(quoteParams.Side)
+	quote.PriceExecuted = clients.NewNullFloat64(price)
+	quote.GrossFeeBps = sim.config.GrossFeeBps
+	quote.RebateBps = sim.config.RebateBps
+	quote.FeeBps = sim.config.GrossFeeBps - sim.config.RebateBps
+	quote.GrossFeeUSD = fees.gross
+	quote.RebateUSD = fees.rebate
+	quote.FeeUSD = fees.gross - fees.rebate
+	sim.executed = append(sim.executed, *quote)
+
+	return *quote, nil
+}
+
+// PlaceOrder fills market orders at the current quote and FOK limit orders only when the
+// quote is within LimitPrice widened by SlippageBps. Unfilled FOK orders return IsFilled false.
+func (sim *Simulator) PlaceOrder(orderParams clients.OrderRequest) (clients.OrderResponse, error) {
+	if err := validateSide(orderParams.Side, false); err != nil {
+		return failedOrder(err), err
+	}
+
+	switch orderParams.OrderType {
+	case "market":
+	case "limit":
+		if orderParams.TimeInForce != "fok" {
+			err := badRequest("Limit orders require time_in_force fok")
+			return failedOrder(err), err
+		}
+		if orderParams.LimitPrice <= 0 {
+			err := badRequest("Limit orders require a positive limit_price")
+			return failedOrder(err), err
+		}
+	default:
+		err := badRequest(fmt.Sprintf("Unknown order_type %s", orderParams.OrderType))
+		return failedOrder(err), err
+	}
+
+	buyPrice, sellPrice, err := sim.prices(orderParams.TokenPair)
+	if err != nil {
+		return failedOrder(err), err
+	}
+
+	now := sim.config.Now().UTC()
+	order := clients.OrderResponse{
+		Status:        statusSuccess,
+		FxQuoteId:     newQuoteID(),
+		Platform:      defaultPlatform,
+		TokenPair:     orderParams.TokenPair,
+		Quantity:      orderParams.Quantity,
+		SideRequested: orderParams.Side,
+		QuoteTime:     now,
+		ExpiryTime:    now.Add(sim.config.QuoteTTL),
+		TraderEmail:   sim.config.TraderEmail,
+		OrderType:     orderParams.OrderType,
+		TimeInForce:   orderParams.TimeInForce,
+		LimitPrice:    limitPrice(orderParams),
+		SlippageBps:   orderParams.SlippageBps,
+		ClientOrderId: orderParams.ClientOrderId,
+	}
+
+	price := buyPrice
+	if orderParams.Side == "sell" {
+		price = sellPrice
+	}
+	if orderParams.Side == "buy" {
+		order.BuyPrice = clients.NewNullFloat64(price)
+	} else {
+		order.SellPrice = clients.NewNullFloat64(price)
+	}
+
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	if orderParams.OrderType == "limit" {
+		slippage := orderParams.SlippageBps / 10000
+		if (orderParams.Side == "buy" && price > orderParams.LimitPrice*(1+slippage)) ||
+			(orderParams.Side == "sell" && price < orderParams.LimitPrice*(1-slippage)) {
+			sim.recordOrder(order)
+			return order, nil
+		}
+	}
+
+	fees, err := sim.settle(orderParams.TokenPair, orderParams.Quantity, orderParams.Side, price, now)
+	if err != nil {
+		return failedOrder(err), err
+	}
+
+	order.IsFilled = true
+	order.ExecutionTime = clients.NewNullTime(now)
+	order.SideExecuted = clients.NewNullSide(orderParams.Side)
+	order.GrossFeeBps = sim.config.GrossFeeBps
+	order.RebateBps = sim.config.RebateBps
+	order.FeeBps = sim.config.GrossFeeBps - sim.config.RebateBps
+	order.GrossFeeUSD = fees.gross
+	order.RebateUSD = fees.rebate
+	order.FeeUSD = fees.gross - fees.rebate
+
+	sim.executed = append(sim.executed, *sim.recordOrder(order))
+
+	return order, nil
+}
+
+// recordOrder stores an order so GetQuoteStatus can find it. Callers must hold sim.mu.
+func (sim *Simulator) recordOrder(order clients.OrderResponse) *clients.QuoteResponse {
+	quote := order.AsQuoteResponse()
+	sim.quotes[quote.FxQuoteId] = &quote
+	return &quote
+}
+
+// GetQuoteStatus returns the current state of a quote or order.
+func (sim *Simulator) GetQuoteStatus(fxQuoteID string) (clients.QuoteResponse, error) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	if quote, ok := sim.quotes[fxQuoteID]; ok {
+		return *quote, nil
+	}
+
+	err := clients.Error{Code: 404, Reason: "Resource Not Found"}
+	return failedQuote(err), err
+}
+
+// GetExecutedQuotes returns simulated fills executed within [tStart, tEnd].
+func (sim *Simulator) GetExecutedQuotes(tStart time.Time, tEnd time.Time) ([]clients.QuoteResponse, error) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	result := make([]clients.QuoteResponse, 0)
+	for _, quote := range sim.executed {
+		if inRange(quote.ExecutionTime.Time, tStart, tEnd) {
+			result = append(result, quote)
+		}
+	}
+	return result, nil
+}
+
+// GetBalances returns the simulated account balances.
+func (sim *Simulator) GetBalances() ([]clients.Balance, error) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	result := make([]clients.Balance, 0, len(sim.balances))
+	for _, token := range sim.tokens() {
+		result = append(result, clients.Balance{Token: token, Balance: sim.balances[token], Platform: defaultPlatform})
+	}
+	return result, nil
+}
+
+// GetTotalBalances returns the simulated balances. The simulator has a single platform,
+// so these always match GetBalances.
+func (sim *Simulator) GetTotalBalances() ([]clients.TotalBalance, error) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	result := make([]clients.TotalBalance, 0, len(sim.balances))
+	for _, token := range sim.tokens() {
+		result = append(result, clients.TotalBalance{Token: token, TotalBalance: sim.balances[token]})
+	}
+	return result, nil
+}
+
+// GetTransfers returns deposits and withdrawals created within [tStart, tEnd].
+func (sim *Simulator) GetTransfers(tStart time.Time, tEnd time.Time) ([]clients.Transfer, error) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	result := make([]clients.Transfer, 0)
+	for _, transfer := range sim.transfers {
+		if inRange(transfer.CreateTime, tStart, tEnd) {
+			result = append(result, transfer)
+		}
+	}
+	return result, nil
+}
+
+// GetTradeVolume sums the USD notional of fills within [tStart, tEnd].
+func (sim *Simulator) GetTradeVolume(tStart time.Time, tEnd time.Time) (clients.TradeVolume, error) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	result := clients.TradeVolume{StartDate: tStart.UTC(), EndDate: tEnd.UTC()}
+	for _, f := range sim.volumes {
+		if inRange(f.time, tStart, tEnd) {
+			result.USDVolume += math.Abs(f.usdValue)
+		}
+	}
+	return result, nil
+}
+
+// GetTradeLimits reports the configured limits against everything traded so far.
+func (sim *Simulator) GetTradeLimits(platform string) (clients.TradeLimits, error) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	var gross, net float64
+	for _, f := range sim.volumes {
+		gross += math.Abs(f.usdValue)
+		net += f.usdValue
+	}
+
+	return clients.TradeLimits{
+		GrossLimits: clients.TradeLimit{Total: sim.config.GrossLimit, Used: gross, Available: sim.config.GrossLimit - gross},
+		NetLimits:   clients.TradeLimit{Total: sim.config.NetLimit, Used: math.Abs(net), Available: sim.config.NetLimit - math.Abs(net)},
+	}, nil
+}
+
+// GetTradeSizes returns the configured size limits.
+func (sim *Simulator) GetTradeSizes() ([]clients.TradeSize, error) {
+	result := make([]clients.TradeSize, 0, len(sim.config.TradeSizes))
+	for pair, limit := range sim.config.TradeSizes {
+		result = append(result, clients.TradeSize{Platform: defaultPlatform, TokenPair: pair, TradeSizeLimitQuoteToken: limit})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].TokenPair, result[j].TokenPair
+		if a.BaseToken != b.BaseToken {
+			return a.BaseToken < b.BaseToken
+		}
+		return a.QuoteToken < b.QuoteToken
+	})
+	return result, nil
+}
+
+type fees struct {
+	gross  float64
+	rebate float64
+}
+
+// settle moves balances for a fill. Callers must hold sim.mu.
+func (sim *Simulator) settle(pair clients.TokenPair, quantity clients.Quantity, side string,
+	price float64, now time.Time) (fees, error) {
+	baseQty, quoteQty := quantity.Value, quantity.Value*price
+	if quantity.Token == pair.QuoteToken {
+		baseQty, quoteQty = quantity.Value/price, quantity.Value
+	}
+
+	if limit, ok := sim.config.TradeSizes[pair]; ok {
+		if quoteQty < limit.Min || (limit.Max > 0 && quoteQty > limit.Max) {
+			return fees{}, badRequest(fmt.Sprintf("Trade size %.8f %s outside [%v, %v]", quoteQty, pair.QuoteToken, limit.Min, limit.Max))
+		}
+	}
+
+	grossFee := quoteQty * sim.config.GrossFeeBps / 10000
+	rebate := quoteQty * sim.config.RebateBps / 10000
+	netFee := grossFee - rebate
+
+	baseDelta, quoteDelta := baseQty, -quoteQty-netFee
+	if side == "sell" {
+		baseDelta, quoteDelta = -baseQty, quoteQty-netFee
+	}
+
+	if !sim.config.AllowNegativeBalances {
+		if sim.balances[pair.BaseToken]+baseDelta < 0 || sim.balances[pair.QuoteToken]+quoteDelta < 0 {
+			return fees{}, badRequest("Insufficient balance")
+		}
+	}
+
+	sim.balances[pair.BaseToken] += baseDelta
+	sim.balances[pair.QuoteToken] += quoteDelta
+
+	usdRate := sim.usdRate(pair.QuoteToken)
+	usdValue := quoteQty * usdRate
+	if side == "sell" {
+		usdValue = -usdValue
+	}
+	sim.volumes = append(sim.volumes, fill{time: now, usdValue: usdValue})
+
+	return fees{gross: grossFee * usdRate, rebate: rebate * usdRate}, nil
+}
+
+func (sim *Simulator) prices(pair clients.TokenPair) (float64, float64, error) {
+	if len(sim.config.Pairs) > 0 && !containsPair(sim.config.Pairs, pair) {
+		return 0, 0, badRequest(fmt.Sprintf("Token pair %s/%s not allowed", pair.BaseToken, pair.QuoteToken))
+	}
+	if sim.config.Prices == nil {
+		return 0, 0, badRequest("No price source configured")
+	}
+
+	mid, err := sim.config.Prices.Price(pair)
+	if err != nil {
+		return 0, 0, badRequest(err.Error())
+	}
+	if mid <= 0 {
+		return 0, 0, badRequest(fmt.Sprintf("Invalid price %v for %s/%s", mid, pair.BaseToken, pair.QuoteToken))
+	}
+
+	half := sim.config.SpreadBps / 2 / 10000
+	return mid * (1 + half), mid * (1 - half), nil
+}
+
+// usdRate converts an amount of token into USD using the price source. Tokens that
+// cannot be priced contribute zero to USD fees and volume.
+func (sim *Simulator) usdRate(token string) float64 {
+	if token == "USD" {
+		return 1
+	}
+	if sim.config.Prices == nil {
+		return 0
+	}
+	rate, err := sim.config.Prices.Price(clients.TokenPair{BaseToken: token, QuoteToken: "USD"})
+	if err != nil {
+		return 0
+	}
+	return rate
+}
+
+func (sim *Simulator) tokens() []string {
+	tokens := make([]string, 0, len(sim.balances))
+	for token := range sim.balances {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// limitPrice returns the order's limit price, null for market orders.
+func limitPrice(orderParams clients.OrderRequest) clients.NullFloat64 {
+	if orderParams.OrderType != "limit" {
+		return clients.NullFloat64{}
+	}
+	return clients.NewNullFloat64(orderParams.LimitPrice)
+}
+
+func validateSide(side string, allowTwoWay bool) error {
+	switch side {
+	case "buy", "sell":
+		return nil
+	case "two_way":
+		if allowTwoWay {
+			return nil
+		}
+	}
+	return badRequest(fmt.Sprintf("Invalid side %q", side))
+}
+
+func containsPair(pairs []clients.TokenPair, pair clients.TokenPair) bool {
+	for _, p := range pairs {
+		if p == pair {
+			return true
+		}
+	}
+	return false
+}
+
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && !t.After(end)
+}
+
+func badRequest(reason string) error {
+	return clients.Error{Code: 400, Reason: reason}
+}
+
+func failedQuote(err error) clients.QuoteResponse {
+	return clients.QuoteResponse{Status: statusFailure, Error: falconXError(err)}
+}
+
+func failedOrder(err error) clients.OrderResponse {
+	return clients.OrderResponse{Status: statusFailure, Error: falconXError(err)}
+}
+
+func falconXError(err error) clients.FalconXError {
+	if e, ok := err.(clients.Error); ok {
+		return clients.FalconXError{Code: fmt.Sprintf("%d", e.Code), Reason: e.Reason}
+	}
+	return clients.FalconXError{Code: "500", Reason: err.Error()}
+}
+
+func newQuoteID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,190 @@
+package simulator_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/falconxio/falconx-go/clients"
+	"github.com/falconxio/falconx-go/simulator"
+)
+
+var btcUSD = clients.TokenPair{BaseToken: "BTC", QuoteToken: "USD"}
+
+// newSimulator quotes BTC/USD at 100 ± 1 with a clock the test moves.
+func newSimulator(now *time.Time, config simulator.Config) *simulator.Simulator {
+	config.Prices = simulator.StaticPrices{btcUSD: 100}
+	config.SpreadBps = 200
+	config.Now = func() time.Time { return *now }
+	return simulator.New(config)
+}
+
+func balances(t *testing.T, sim *simulator.Simulator) map[string]float64 {
+	t.Helper()
+	result := make(map[string]float64)
+	list, err := sim.GetBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, balance := range list {
+		result[balance.Token] = balance.Balance
+	}
+	return result
+}
+
+func TestQuoteExpiry(t *testing.T) {
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	sim := newSimulator(&now, simulator.Config{QuoteTTL: 5 * time.Second, Balances: map[string]float64{"USD": 1000}})
+
+	request := clients.QuoteRequest{TokenPair: btcUSD, Quantity: clients.Quantity{Token: "BTC", Value: 1}, Side: "buy"}
+	first, err := sim.GetQuote(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price, _ := first.BuyPrice.Get(); price != 101 || first.SellPrice.Valid {
+		t.Errorf("buy quote priced %v, sell valid %v; want 101 and no sell price", price, first.SellPrice.Valid)
+	}
+	second, err := sim.GetQuote(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(5*time.Second - time.Nanosecond)
+	filled, err := sim.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: first.FxQuoteId, Side: "buy"})
+	if err != nil {
+		t.Fatalf("executing before expiry: %v", err)
+	}
+	if !filled.IsFilled || filled.ExecutedPrice() != 101 {
+		t.Errorf("filled %v at %v, want a fill at 101", filled.IsFilled, filled.ExecutedPrice())
+	}
+
+	now = now.Add(time.Nanosecond)
+	expired, err := sim.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: second.FxQuoteId, Side: "buy"})
+	if err == nil || expired.IsFilled {
+		t.Fatal("quote executed at t_expiry")
+	}
+	if expired.Status != "failure" || expired.Error.Reason != "Quote expired" {
+		t.Errorf("status %q, reason %q, want a Quote expired failure", expired.Status, expired.Error.Reason)
+	}
+
+	if _, err := sim.ExecuteQuote(clients.QuoteExecutionRequest{FxQuoteId: first.FxQuoteId, Side: "buy"}); err == nil {
+		t.Error("quote executed twice")
+	}
+	if got := balances(t, sim); got["BTC"] != 1 || got["USD"] != 899 {
+		t.Errorf("balances = %v, want 1 BTC and 899 USD", got)
+	}
+}
+
+func TestLimitOrderSlippage(t *testing.T) {
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		side        string
+		limit       float64
+		slippageBps float64
+		filled      bool
+	}{
+		{"buy", 101, 0, true},
+		{"buy", 100, 100, true},
+		{"buy", 100, 50, false},
+		{"sell", 99, 0, true},
+		{"sell", 100, 100, true},
+		{"sell", 100, 50, false},
+	} {
+		sim := newSimulator(&now, simulator.Config{Balances: map[string]float64{"USD": 1000, "BTC": 1}})
+		order, err := sim.PlaceOrder(clients.OrderRequest{
+			TokenPair:   btcUSD,
+			Quantity:    clients.Quantity{Token: "BTC", Value: 1},
+			Side:        test.side,
+			OrderType:   "limit",
+			TimeInForce: "fok",
+			LimitPrice:  test.limit,
+			SlippageBps: test.slippageBps,
+		})
+		if err != nil {
+			t.Fatalf("%s at %v ± %v bps: %v", test.side, test.limit, test.slippageBps, err)
+		}
+		if order.IsFilled != test.filled {
+			t.Errorf("%s at %v ± %v bps: filled %v, want %v", test.side, test.limit, test.slippageBps, order.IsFilled, test.filled)
+		}
+
+		want := map[string]float64{"USD": 1000, "BTC": 1}
+		if test.filled && test.side == "buy" {
+			want = map[string]float64{"USD": 899, "BTC": 2}
+		} else if test.filled {
+			want = map[string]float64{"USD": 1099, "BTC": 0}
+		}
+		if got := balances(t, sim); got["USD"] != want["USD"] || got["BTC"] != want["BTC"] {
+			t.Errorf("%s at %v ± %v bps: balances %v, want %v", test.side, test.limit, test.slippageBps, got, want)
+		}
+
+		status, err := sim.GetQuoteStatus(order.FxQuoteId)
+		if err != nil || status.IsFilled != test.filled {
+			t.Errorf("GetQuoteStatus = filled %v, %v; want %v", status.IsFilled, err, test.filled)
+		}
+	}
+}
+
+func TestFeesAndLimits(t *testing.T) {
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	sim := newSimulator(&now, simulator.Config{
+		GrossFeeBps: 10,
+		RebateBps:   4,
+		Balances:    map[string]float64{"USD": 101},
+		TradeSizes:  map[clients.TokenPair]clients.TradeSizeLimit{btcUSD: {Min: 10, Max: 200}},
+	})
+
+	market := clients.OrderRequest{TokenPair: btcUSD, Quantity: clients.Quantity{Token: "BTC", Value: 1}, Side: "buy", OrderType: "market"}
+	if _, err := sim.PlaceOrder(market); err == nil {
+		t.Error("order filled without the balance to pay its fee")
+	}
+
+	sim.Deposit("USD", 99)
+	order, err := sim.PlaceOrder(market)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(order.FeeUSD-0.0606) > 1e-9 || order.FeeBps != 6 {
+		t.Errorf("fee %v USD at %v bps, want 0.0606 at 6", order.FeeUSD, order.FeeBps)
+	}
+	if got := balances(t, sim)["USD"]; math.Abs(got-(200-101-0.0606)) > 1e-9 {
+		t.Errorf("USD balance = %v, want %v", got, 200-101-0.0606)
+	}
+
+	small := market
+	small.Quantity.Value = 0.01
+	if _, err := sim.PlaceOrder(small); err == nil {
+		t.Error("order below the minimum trade size filled")
+	}
+
+	executed, err := sim.GetExecutedQuotes(now, now)
+	if err != nil || len(executed) != 1 {
+		t.Errorf("GetExecutedQuotes = %d quotes, %v; want 1", len(executed), err)
+	}
+	volume, err := sim.GetTradeVolume(now, now)
+	if err != nil || volume.USDVolume != 101 {
+		t.Errorf("GetTradeVolume = %v, %v; want 101", volume.USDVolume, err)
+	}
+}
+
+func TestGetTradingPairs(t *testing.T) {
+	ethUSD := clients.TokenPair{BaseToken: "ETH", QuoteToken: "USD"}
+	btcEUR := clients.TokenPair{BaseToken: "BTC", QuoteToken: "EUR"}
+	prices := simulator.StaticPrices{ethUSD: 10, btcUSD: 100, btcEUR: 90}
+	tests := []struct {
+		name   string
+		config simulator.Config
+		want   []clients.TokenPair
+	}{
+		{"configured pairs", simulator.Config{Prices: prices, Pairs: []clients.TokenPair{ethUSD}}, []clients.TokenPair{ethUSD}},
+		{"static prices", simulator.Config{Prices: prices}, []clients.TokenPair{btcEUR, btcUSD, ethUSD}},
+		{"unlisted source", simulator.Config{Prices: simulator.PriceSourceFunc(prices.Price)}, []clients.TokenPair{}},
+	}
+	for _, test := range tests {
+		pairs, err := simulator.New(test.config).GetTradingPairs()
+		if err != nil || !reflect.DeepEqual(pairs, test.want) {
+			t.Errorf("%s: GetTradingPairs = %v, %v; want %v", test.name, pairs, err, test.want)
+		}
+	}
+}